@@ -0,0 +1,215 @@
+// Copyright (C) 2015 Scott Devoid
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package pivotal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	ChangeTypeCreate = "create"
+	ChangeTypeUpdate = "update"
+	ChangeTypeDelete = "delete"
+)
+
+// Activity kinds as reported in Activity.Kind.
+const (
+	ActivityKindStoryCreate   = "story_create_activity"
+	ActivityKindStoryUpdate   = "story_update_activity"
+	ActivityKindStoryDelete   = "story_delete_activity"
+	ActivityKindStoryMove     = "story_move_activity"
+	ActivityKindCommentCreate = "comment_create_activity"
+	ActivityKindCommentUpdate = "comment_update_activity"
+	ActivityKindCommentDelete = "comment_delete_activity"
+	ActivityKindTaskCreate    = "task_create_activity"
+	ActivityKindTaskUpdate    = "task_update_activity"
+	ActivityKindTaskDelete    = "task_delete_activity"
+	ActivityKindEpicCreate    = "epic_create_activity"
+	ActivityKindEpicUpdate    = "epic_update_activity"
+	ActivityKindEpicDelete    = "epic_delete_activity"
+	ActivityKindLabelCreate   = "label_create_activity"
+	ActivityKindLabelUpdate   = "label_update_activity"
+	ActivityKindLabelDelete   = "label_delete_activity"
+)
+
+// Activity describes a single change made to a project, e.g. a story
+// being moved or a comment being added.
+type Activity struct {
+	Kind               string              `json:"kind,omitempty"`
+	GUID               string              `json:"guid,omitempty"`
+	ProjectVersion     int                 `json:"project_version,omitempty"`
+	Message            string              `json:"message,omitempty"`
+	Highlight          string              `json:"highlight,omitempty"`
+	Changes            []*ActivityChange   `json:"changes,omitempty"`
+	PrimaryResources   []*ActivityResource `json:"primary_resources,omitempty"`
+	SecondaryResources []*ActivityResource `json:"secondary_resources,omitempty"`
+	Project            *ActivityResource   `json:"project,omitempty"`
+	PerformedBy        *Person             `json:"performed_by,omitempty"`
+	OccurredAt         *time.Time          `json:"occurred_at,omitempty"`
+}
+
+// ActivityChange is a change to a single resource. OriginalValues and
+// NewValues hold only the attributes that changed, in the JSON shape of
+// the resource named by Kind; use Decode to unmarshal them.
+type ActivityChange struct {
+	Kind           string          `json:"kind,omitempty"`
+	ChangeType     string          `json:"change_type,omitempty"`
+	Id             int             `json:"id,omitempty"`
+	OriginalValues json.RawMessage `json:"original_values,omitempty"`
+	NewValues      json.RawMessage `json:"new_values,omitempty"`
+	Name           string          `json:"name,omitempty"`
+	StoryType      string          `json:"story_type,omitempty"`
+}
+
+// Decode unmarshals the original and new values of the change into the
+// given pointers, e.g. two *Story for a change of kind "story". Either
+// pointer may be nil, and values absent from the change are left alone.
+func (c *ActivityChange) Decode(original, new interface{}) error {
+	if original != nil && len(c.OriginalValues) != 0 {
+		if err := json.Unmarshal(c.OriginalValues, original); err != nil {
+			return err
+		}
+	}
+	if new != nil && len(c.NewValues) != 0 {
+		if err := json.Unmarshal(c.NewValues, new); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Story decodes a change of kind "story".
+func (c *ActivityChange) Story() (original, new *Story, err error) {
+	original, new = &Story{}, &Story{}
+	return original, new, c.decodeKind("story", original, new)
+}
+
+// Task decodes a change of kind "task".
+func (c *ActivityChange) Task() (original, new *Task, err error) {
+	original, new = &Task{}, &Task{}
+	return original, new, c.decodeKind("task", original, new)
+}
+
+// Comment decodes a change of kind "comment".
+func (c *ActivityChange) Comment() (original, new *Comment, err error) {
+	original, new = &Comment{}, &Comment{}
+	return original, new, c.decodeKind("comment", original, new)
+}
+
+// Epic decodes a change of kind "epic".
+func (c *ActivityChange) Epic() (original, new *Epic, err error) {
+	original, new = &Epic{}, &Epic{}
+	return original, new, c.decodeKind("epic", original, new)
+}
+
+// Label decodes a change of kind "label".
+func (c *ActivityChange) Label() (original, new *Label, err error) {
+	original, new = &Label{}, &Label{}
+	return original, new, c.decodeKind("label", original, new)
+}
+
+func (c *ActivityChange) decodeKind(kind string, original, new interface{}) error {
+	if c.Kind != kind {
+		return fmt.Errorf("change is of kind '%s', not '%s'", c.Kind, kind)
+	}
+	return c.Decode(original, new)
+}
+
+// ActivityResource identifies a resource an activity refers to.
+type ActivityResource struct {
+	Kind      string `json:"kind,omitempty"`
+	Id        int    `json:"id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	StoryType string `json:"story_type,omitempty"`
+	URL       string `json:"url,omitempty"`
+}
+
+// ActivityService provides an activity feed. Obtain one from
+// ProjectService.Activity, StoryService.Activity, EpicService.Activity
+// or MeService.Activity.
+type ActivityService struct {
+	client *Client
+	path   string
+}
+
+func newActivityService(client *Client, path string) *ActivityService {
+	return &ActivityService{client, path}
+}
+
+func (s *ActivityService) setupReq(ctx context.Context, opts []RequestOption) (req *http.Request, err error) {
+	req, err = s.client.NewRequestContext(ctx, "GET", s.path, nil)
+	if err != nil {
+		return
+	}
+	for _, opt := range opts {
+		opt(req)
+	}
+	return
+}
+
+func (s *ActivityService) List(opts ...RequestOption) ([]*Activity, *http.Response, error) {
+	return s.ListContext(context.Background(), opts...)
+}
+
+func (s *ActivityService) ListContext(ctx context.Context, opts ...RequestOption) ([]*Activity, *http.Response, error) {
+	req, err := s.setupReq(ctx, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	var activity []*Activity
+	resp, err := s.client.Do(req, &activity)
+	if err != nil {
+		return nil, resp, err
+	}
+	return activity, resp, err
+}
+
+type ActivityCursor = Cursor[Activity]
+
+func (s *ActivityService) Iterate(opts ...RequestOption) (c *ActivityCursor, err error) {
+	return s.IterateContext(context.Background(), opts...)
+}
+
+func (s *ActivityService) IterateContext(ctx context.Context, opts ...RequestOption) (c *ActivityCursor, err error) {
+	req_fn := func(ctx context.Context) (req *http.Request) {
+		req, _ = s.setupReq(ctx, opts)
+		return req
+	}
+	return newTypedCursor[Activity](ctx, s.client, req_fn)
+}
+
+// IterateFrom resumes iterating at a position saved with Cursor.Position.
+func (s *ActivityService) IterateFrom(pos CursorPosition) (*ActivityCursor, error) {
+	return s.IterateFromContext(context.Background(), pos)
+}
+
+func (s *ActivityService) IterateFromContext(ctx context.Context, pos CursorPosition) (*ActivityCursor, error) {
+	req_fn := func(ctx context.Context) (req *http.Request) {
+		req, _ = s.setupReq(ctx, nil)
+		return req
+	}
+	return resumeTypedCursor[Activity](ctx, s.client, req_fn, pos)
+}
+
+// Activity returns the activity feed of the given story.
+func (service *StoryService) Activity(storyId int) *ActivityService {
+	u := fmt.Sprintf("projects/%v/stories/%v/activity", service.projectId, storyId)
+	return newActivityService(service.client, u)
+}
+
+// Activity returns the activity feed of the given epic.
+func (s *EpicService) Activity(epicId int) *ActivityService {
+	u := fmt.Sprintf("projects/%v/epics/%v/activity", s.projectId, epicId)
+	return newActivityService(s.Client, u)
+}
+
+// Activity returns the activity feed of the authenticated user.
+func (service *MeService) Activity() *ActivityService {
+	return newActivityService(service.client, "my/activity")
+}
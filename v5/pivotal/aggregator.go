@@ -0,0 +1,221 @@
+// Copyright (C) 2015 Scott Devoid
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package pivotal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// DefaultAggregatorChunkSize is the number of GET requests an Aggregator
+// sends to Tracker at once unless configured otherwise.
+const DefaultAggregatorChunkSize = 20
+
+// ErrNotSent is returned by Pending.Value before the Aggregator was sent.
+var ErrNotSent = errors.New("aggregated request not sent yet")
+
+// Aggregator batches GET requests into calls of the '/aggregator'
+// endpoint, which runs many requests in a single round trip:
+//
+//	agg := client.NewAggregator()
+//	story := agg.Story(projectId, storyId)
+//	tasks := agg.Tasks(projectId, storyId)
+//	if err := agg.Send(); err != nil {
+//		return err
+//	}
+//	s, err := story.Value()
+//
+// An Aggregator is not safe for concurrent use.
+type Aggregator struct {
+	client *Client
+
+	// ChunkSize is the number of requests sent per call, defaults to
+	// DefaultAggregatorChunkSize.
+	ChunkSize int
+
+	pending []aggregated
+}
+
+// aggregated is a request queued in an Aggregator.
+type aggregated interface {
+	url() *url.URL
+	resolve(body json.RawMessage, err error)
+}
+
+// Pending is the result of a request queued in an Aggregator. It becomes
+// available once the Aggregator was sent.
+type Pending[T any] struct {
+	u     *url.URL
+	value T
+	err   error
+	done  bool
+}
+
+// Value returns the decoded response, or the error Tracker returned for
+// this request alone. It returns ErrNotSent until the Aggregator was sent.
+func (p *Pending[T]) Value() (T, error) {
+	if !p.done {
+		var zero T
+		return zero, ErrNotSent
+	}
+	return p.value, p.err
+}
+
+func (p *Pending[T]) url() *url.URL {
+	return p.u
+}
+
+func (p *Pending[T]) resolve(body json.RawMessage, err error) {
+	p.done = true
+	if err != nil {
+		p.err = err
+		return
+	}
+	var e Error
+	var kind struct {
+		Kind string `json:"kind"`
+	}
+	if json.Unmarshal(body, &kind) == nil && kind.Kind == "error" {
+		json.Unmarshal(body, &e)
+		p.err = aggregatedError(p.u, &e, body)
+		return
+	}
+	p.err = json.Unmarshal(body, &p.value)
+}
+
+// aggregatedError turns an error body of an aggregated request into an
+// *ErrAPI. Tracker does not report the status of single requests, so it is
+// derived from the error code to keep errors.Is working.
+func aggregatedError(u *url.URL, e *Error, body []byte) error {
+	status := http.StatusBadRequest
+	switch e.Code {
+	case "unfound_resource", "route_not_found":
+		status = http.StatusNotFound
+	case "unauthenticated", "invalid_authentication":
+		status = http.StatusUnauthorized
+	case "unauthorized_operation":
+		status = http.StatusForbidden
+	}
+	return &ErrAPI{
+		Response: &http.Response{
+			Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+			StatusCode: status,
+			Request:    &http.Request{Method: "GET", URL: u},
+		},
+		Err:      e,
+		Body:     body,
+		Attempts: 1,
+	}
+}
+
+// NewAggregator returns an empty Aggregator using the client.
+func (c *Client) NewAggregator() *Aggregator {
+	return &Aggregator{client: c, ChunkSize: DefaultAggregatorChunkSize}
+}
+
+// addPending queues a GET of the given API path.
+func addPending[T any](a *Aggregator, path string) *Pending[T] {
+	u := a.client.baseURL.ResolveReference(&url.URL{Path: path})
+	p := &Pending[T]{u: u}
+	a.pending = append(a.pending, p)
+	return p
+}
+
+// Story queues a request for a story.
+func (a *Aggregator) Story(projectId, storyId int) *Pending[*Story] {
+	return addPending[*Story](a, fmt.Sprintf("projects/%v/stories/%v", projectId, storyId))
+}
+
+// Tasks queues a request for the tasks of a story.
+func (a *Aggregator) Tasks(projectId, storyId int) *Pending[[]*Task] {
+	return addPending[[]*Task](a, fmt.Sprintf("projects/%v/stories/%v/tasks", projectId, storyId))
+}
+
+// Owners queues a request for the owners of a story.
+func (a *Aggregator) Owners(projectId, storyId int) *Pending[[]*Person] {
+	return addPending[[]*Person](a, fmt.Sprintf("projects/%v/stories/%v/owners", projectId, storyId))
+}
+
+// Labels queues a request for the labels of a project.
+func (a *Aggregator) Labels(projectId int) *Pending[[]*Label] {
+	return addPending[[]*Label](a, fmt.Sprintf("projects/%v/labels", projectId))
+}
+
+// Epic queues a request for an epic.
+func (a *Aggregator) Epic(projectId, epicId int) *Pending[*Epic] {
+	return addPending[*Epic](a, fmt.Sprintf("projects/%v/epics/%v", projectId, epicId))
+}
+
+// Epics queues a request for the epics of a project.
+func (a *Aggregator) Epics(projectId int) *Pending[[]*Epic] {
+	return addPending[[]*Epic](a, fmt.Sprintf("projects/%v/epics", projectId))
+}
+
+// Len returns the number of requests waiting to be sent.
+func (a *Aggregator) Len() int {
+	return len(a.pending)
+}
+
+// Send sends the queued requests in chunks and resolves their Pending
+// values. Errors of single requests are reported by Pending.Value; the
+// returned error is set when a whole chunk failed, in which case every
+// request of that and the following chunks carries it as well.
+func (a *Aggregator) Send() error {
+	return a.SendContext(context.Background())
+}
+
+func (a *Aggregator) SendContext(ctx context.Context) error {
+	size := a.ChunkSize
+	if size <= 0 {
+		size = DefaultAggregatorChunkSize
+	}
+	pending := a.pending
+	a.pending = nil
+
+	for len(pending) > 0 {
+		n := min(size, len(pending))
+		chunk := pending[:n]
+		pending = pending[n:]
+		if err := a.send(ctx, chunk); err != nil {
+			for _, p := range append(chunk, pending...) {
+				p.resolve(nil, err)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *Aggregator) send(ctx context.Context, chunk []aggregated) error {
+	urls := make([]string, len(chunk))
+	for i, p := range chunk {
+		urls[i] = p.url().String()
+	}
+	req, err := a.client.NewRequestContext(ctx, "POST", "aggregator", urls)
+	if err != nil {
+		return err
+	}
+	var bodies map[string]json.RawMessage
+	if _, err := a.client.Do(req, &bodies); err != nil {
+		return err
+	}
+	for i, p := range chunk {
+		body, ok := bodies[urls[i]]
+		if !ok {
+			// Tracker may key the results by path rather than full URL.
+			body, ok = bodies[p.url().RequestURI()]
+		}
+		if !ok {
+			p.resolve(nil, fmt.Errorf("no response for %v in aggregated result", urls[i]))
+			continue
+		}
+		p.resolve(body, nil)
+	}
+	return nil
+}
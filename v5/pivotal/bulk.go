@@ -0,0 +1,301 @@
+// Copyright (C) 2015 Scott Devoid
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package pivotal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBulkWorkers      = 4
+	defaultRateLimitPause   = 5 * time.Second
+	defaultRateLimitRetries = 5
+)
+
+// StoryEditFunc changes a story in place. Only the fields it changes are
+// sent to Tracker, see UpdateFrom. Returning an error skips the story.
+type StoryEditFunc func(story *Story) error
+
+// BulkOptions configures BulkUpdate. A nil *BulkOptions uses the defaults.
+type BulkOptions struct {
+	// Workers is the number of stories updated at a time, 4 by default.
+	Workers int
+
+	// IfUnmodified makes every update fail with an *ErrConflict when the
+	// story changed since it was read, see IfUnmodified.
+	IfUnmodified bool
+
+	// RateLimitPause is how long all workers wait after Tracker reported
+	// a rate limit without a Retry-After header, 5 seconds by default.
+	RateLimitPause time.Duration
+
+	// RateLimitRetries is how often a single story is retried after being
+	// rate limited, 5 by default.
+	RateLimitRetries int
+}
+
+// BulkResult is the outcome of updating a single story.
+type BulkResult struct {
+	StoryId int
+
+	// Story is the updated story, or the story as read when nothing
+	// changed or the update failed.
+	Story    *Story
+	Response *http.Response
+	Err      error
+}
+
+// BulkReport lists the results of a bulk update in input order.
+type BulkReport []*BulkResult
+
+// Succeeded returns the results of the stories that were updated or
+// needed no update.
+func (r BulkReport) Succeeded() []*BulkResult {
+	return r.filter(func(res *BulkResult) bool { return res.Err == nil })
+}
+
+// Failed returns the results of the stories that could not be updated.
+func (r BulkReport) Failed() []*BulkResult {
+	return r.filter(func(res *BulkResult) bool { return res.Err != nil })
+}
+
+func (r BulkReport) filter(keep func(*BulkResult) bool) []*BulkResult {
+	var results []*BulkResult
+	for _, res := range r {
+		if keep(res) {
+			results = append(results, res)
+		}
+	}
+	return results
+}
+
+// BulkUpdate fetches the given stories, applies fn to each and sends the
+// changes. Failures of single stories are reported in the BulkReport;
+// the error is only set when the operation as a whole was aborted.
+func (service *StoryService) BulkUpdate(storyIds []int, fn StoryEditFunc, opts *BulkOptions) (BulkReport, error) {
+	return service.BulkUpdateContext(context.Background(), storyIds, fn, opts)
+}
+
+func (service *StoryService) BulkUpdateContext(ctx context.Context, storyIds []int, fn StoryEditFunc, opts *BulkOptions) (BulkReport, error) {
+	return service.bulk(ctx, fn, opts, func(ctx context.Context, items chan<- bulkItem) error {
+		for i, id := range storyIds {
+			select {
+			case items <- bulkItem{index: i, storyId: id}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+}
+
+// BulkUpdateCursor is like BulkUpdate, but applies fn to the stories
+// returned by cursor, which saves fetching each story again.
+func (service *StoryService) BulkUpdateCursor(cursor *StoryCursor, fn StoryEditFunc, opts *BulkOptions) (BulkReport, error) {
+	return service.BulkUpdateCursorContext(context.Background(), cursor, fn, opts)
+}
+
+func (service *StoryService) BulkUpdateCursorContext(ctx context.Context, cursor *StoryCursor, fn StoryEditFunc, opts *BulkOptions) (BulkReport, error) {
+	return service.bulk(ctx, fn, opts, func(ctx context.Context, items chan<- bulkItem) error {
+		for i := 0; ; i++ {
+			story, err := cursor.Next()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			select {
+			case items <- bulkItem{index: i, storyId: story.Id, story: story}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	})
+}
+
+type bulkItem struct {
+	index   int
+	storyId int
+	story   *Story
+}
+
+// bulk runs fn on the items sent by feed using a pool of workers.
+func (service *StoryService) bulk(ctx context.Context, fn StoryEditFunc, opts *BulkOptions,
+	feed func(context.Context, chan<- bulkItem) error) (BulkReport, error) {
+
+	var o BulkOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Workers <= 0 {
+		o.Workers = defaultBulkWorkers
+	}
+	if o.RateLimitPause <= 0 {
+		o.RateLimitPause = defaultRateLimitPause
+	}
+	if o.RateLimitRetries <= 0 {
+		o.RateLimitRetries = defaultRateLimitRetries
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		items   = make(chan bulkItem)
+		gate    = &rateGate{}
+		lock    sync.Mutex
+		indexes []int
+		report  BulkReport
+		wg      sync.WaitGroup
+	)
+	for i := 0; i < o.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range items {
+				res := service.bulkOne(ctx, gate, &o, fn, item)
+				lock.Lock()
+				indexes = append(indexes, item.index)
+				report = append(report, res)
+				lock.Unlock()
+			}
+		}()
+	}
+
+	err := feed(ctx, items)
+	close(items)
+	wg.Wait()
+
+	sort.Sort(byIndex{indexes, report})
+	return report, err
+}
+
+// bulkOne fetches the story if needed, applies fn and sends the changes,
+// retrying each request after the shared pause when rate limited.
+func (service *StoryService) bulkOne(ctx context.Context, gate *rateGate, o *BulkOptions, fn StoryEditFunc, item bulkItem) *BulkResult {
+	res := &BulkResult{StoryId: item.storyId, Story: item.story}
+	call := func(do func() (*Story, *http.Response, error)) (*Story, error) {
+		for attempt := 0; ; attempt++ {
+			if err := gate.wait(ctx); err != nil {
+				return nil, err
+			}
+			story, resp, err := do()
+			res.Response = resp
+			if !errors.Is(err, ErrRateLimited) || attempt >= o.RateLimitRetries {
+				return story, err
+			}
+			pause := o.RateLimitPause
+			if resp != nil {
+				if d, ok := retryAfter(resp); ok {
+					pause = d
+				}
+			}
+			gate.pause(pause)
+		}
+	}
+
+	if res.Story == nil {
+		story, err := call(func() (*Story, *http.Response, error) {
+			return service.GetContext(ctx, item.storyId)
+		})
+		if err != nil {
+			res.Err = err
+			return res
+		}
+		res.Story = story
+	}
+
+	modified, err := copyStory(res.Story)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	if err := fn(modified); err != nil {
+		res.Err = err
+		return res
+	}
+
+	var updateOpts []UpdateOption
+	if o.IfUnmodified {
+		updateOpts = append(updateOpts, IfUnmodified())
+	}
+	story, err := call(func() (*Story, *http.Response, error) {
+		return service.UpdateFromContext(ctx, res.Story, modified, updateOpts...)
+	})
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	res.Story = story
+	return res
+}
+
+// copyStory returns a deep copy of story, so that fn cannot change the
+// original through the pointers and slices both would otherwise share.
+func copyStory(story *Story) (*Story, error) {
+	b, err := json.Marshal(story)
+	if err != nil {
+		return nil, err
+	}
+	var c Story
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// rateGate holds back all workers of a bulk operation while Tracker is
+// rate limiting it.
+type rateGate struct {
+	lock  sync.Mutex
+	until time.Time
+}
+
+func (g *rateGate) pause(d time.Duration) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if until := time.Now().Add(d); until.After(g.until) {
+		g.until = until
+	}
+}
+
+func (g *rateGate) wait(ctx context.Context) error {
+	for {
+		g.lock.Lock()
+		d := time.Until(g.until)
+		g.lock.Unlock()
+		if d <= 0 {
+			return ctx.Err()
+		}
+		timer := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// byIndex sorts a report by the input position of its stories.
+type byIndex struct {
+	indexes []int
+	report  BulkReport
+}
+
+func (s byIndex) Len() int           { return len(s.indexes) }
+func (s byIndex) Less(i, j int) bool { return s.indexes[i] < s.indexes[j] }
+func (s byIndex) Swap(i, j int) {
+	s.indexes[i], s.indexes[j] = s.indexes[j], s.indexes[i]
+	s.report[i], s.report[j] = s.report[j], s.report[i]
+}
@@ -0,0 +1,48 @@
+// Copyright (C) 2015 Scott Devoid
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package pivotal_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/salsita/go-pivotaltracker/v5/pivotal"
+	"github.com/salsita/go-pivotaltracker/v5/pivotal/pivotaltest"
+)
+
+func TestBulkUpdateRelabelThroughPointer(t *testing.T) {
+	srv := pivotaltest.NewServer()
+	defer srv.Close()
+	p := srv.AddProject(pivotal.Project{Name: "Test"})
+	old := srv.AddLabel(p.Id, pivotal.Label{Name: "old"})
+	label := srv.AddLabel(p.Id, pivotal.Label{Name: "new"})
+	story := srv.AddStory(p.Id, pivotal.Story{
+		Name:     "Relabel me",
+		LabelIds: &[]int{old.Id},
+	})
+
+	stories := srv.Client().Project(p.Id).Stories
+	report, err := stories.BulkUpdate([]int{story.Id}, func(s *pivotal.Story) error {
+		*s.LabelIds = append(*s.LabelIds, label.Id)
+		return nil
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if failed := report.Failed(); len(failed) != 0 {
+		t.Fatalf("update failed: %v", failed[0].Err)
+	}
+	if report[0].Response == nil {
+		t.Fatal("no request was sent")
+	}
+
+	got, _, err := stories.Get(story.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.LabelIds == nil || !slices.Equal(*got.LabelIds, []int{old.Id, label.Id}) {
+		t.Errorf("label IDs = %v, want [%d %d]", got.LabelIds, old.Id, label.Id)
+	}
+}
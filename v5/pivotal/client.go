@@ -6,11 +6,14 @@ package pivotal
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"net/url"
 	"strconv"
+	"sync"
 )
 
 const (
@@ -35,14 +38,28 @@ type Client struct {
 	// User-Agent header to use when connecting to the Pivotal Tracker API.
 	userAgent string
 
+	// Retry policy applied by Do, nil when retrying is disabled.
+	retry *RetryPolicy
+
+	// Middleware invoked by NewRequest and Do.
+	middleware []Middleware
+
+	// Whether writes are validated before being sent, see WithValidation,
+	// and the project configurations cached for that purpose.
+	validate     bool
+	projectCache sync.Map
+
 	// Me service
 	Me *MeService
 
 	// Story service
 	Stories *StoryServiceShim
+
+	// Project service
+	Projects *ProjectsService
 }
 
-func NewClient(apiToken string) *Client {
+func NewClient(apiToken string, opts ...ClientOption) *Client {
 	baseURL, _ := url.Parse(defaultBaseURL)
 	client := &Client{
 		token:     apiToken,
@@ -50,8 +67,12 @@ func NewClient(apiToken string) *Client {
 		baseURL:   baseURL,
 		userAgent: defaultUserAgent,
 	}
+	for _, opt := range opts {
+		opt(client)
+	}
 	client.Me = newMeService(client)
 	client.Stories = newStoryServiceShim(client)
+	client.Projects = newProjectsService(client)
 	return client
 }
 
@@ -73,7 +94,14 @@ func (c *Client) SetUserAgent(agent string) {
 	c.userAgent = agent
 }
 
+// NewRequest is like NewRequestContext but uses context.Background().
 func (c *Client) NewRequest(method, urlPath string, body interface{}) (*http.Request, error) {
+	return c.NewRequestContext(context.Background(), method, urlPath, body)
+}
+
+// NewRequestContext builds an API request bound to ctx, so cancelling ctx
+// or hitting its deadline aborts the request once it is passed to Do.
+func (c *Client) NewRequestContext(ctx context.Context, method, urlPath string, body interface{}) (*http.Request, error) {
 	path, err := url.Parse(urlPath)
 	if err != nil {
 		return nil, err
@@ -88,7 +116,7 @@ func (c *Client) NewRequest(method, urlPath string, body interface{}) (*http.Req
 		}
 	}
 
-	req, err := http.NewRequest(method, u.String(), buf)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), buf)
 	if err != nil {
 		return nil, err
 	}
@@ -100,22 +128,29 @@ func (c *Client) NewRequest(method, urlPath string, body interface{}) (*http.Req
 }
 
 func (c *Client) Do(req *http.Request, v interface{}) (*http.Response, error) {
-	resp, err := c.client.Do(req)
+	resp, attempts, err := c.send(req)
 	if err != nil {
 		return nil, err
 	}
 
 	defer resp.Body.Close()
 
+	if err := c.processResponse(resp); err != nil {
+		return resp, err
+	}
+
 	if resp.StatusCode > 299 {
+		body, _ := io.ReadAll(resp.Body)
 		var errObject Error
-		if err := json.NewDecoder(resp.Body).Decode(&errObject); err != nil {
-			return resp, &ErrAPI{Response: resp}
+		if err := json.Unmarshal(body, &errObject); err != nil {
+			return resp, &ErrAPI{Response: resp, Body: body, Attempts: attempts}
 		}
 
 		return resp, &ErrAPI{
 			Response: resp,
 			Err:      &errObject,
+			Body:     body,
+			Attempts: attempts,
 		}
 	}
 
@@ -133,6 +168,9 @@ type ProjectService struct {
 	Labels     *LabelService
 	Epics      *EpicService
 	Iterations *IterationService
+	Activity   *ActivityService
+	Webhooks   *WebhookService
+	Search     *SearchService
 }
 
 func newProjectService(c *Client, projectId int) *ProjectService {
@@ -142,6 +180,9 @@ func newProjectService(c *Client, projectId int) *ProjectService {
 	p.Labels = newLabelService(p.Client, id)
 	p.Epics = newEpicService(p.Client, id)
 	p.Iterations = newIterationService(p.Client, id)
+	p.Activity = newActivityService(p.Client, "projects/"+id+"/activity")
+	p.Webhooks = newWebhookService(p.Client, id)
+	p.Search = newSearchService(p.Client, id)
 	return p
 }
 
@@ -0,0 +1,149 @@
+// Copyright (C) 2015 Scott Devoid
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package pivotal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// CommentService provides the comment endpoints of a single story or epic.
+// Obtain one with StoryService.Comments or EpicService.Comments.
+type CommentService struct {
+	client *Client
+	path   string
+}
+
+func newCommentService(client *Client, parentPath string) *CommentService {
+	return &CommentService{client, parentPath + "/comments"}
+}
+
+func (s *CommentService) List(opts ...RequestOption) ([]*Comment, *http.Response, error) {
+	return s.ListContext(context.Background(), opts...)
+}
+
+func (s *CommentService) ListContext(ctx context.Context, opts ...RequestOption) ([]*Comment, *http.Response, error) {
+	req, err := s.client.NewRequestContext(ctx, "GET", s.path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, opt := range opts {
+		opt(req)
+	}
+	var comments []*Comment
+	resp, err := s.client.Do(req, &comments)
+	if err != nil {
+		return nil, resp, err
+	}
+	return comments, resp, err
+}
+
+type CommentCursor = Cursor[Comment]
+
+func (s *CommentService) Iterate(opts ...RequestOption) (*CommentCursor, error) {
+	return s.IterateContext(context.Background(), opts...)
+}
+
+func (s *CommentService) IterateContext(ctx context.Context, opts ...RequestOption) (*CommentCursor, error) {
+	req_fn := func(ctx context.Context) (req *http.Request) {
+		req, _ = s.client.NewRequestContext(ctx, "GET", s.path, nil)
+		if req != nil {
+			for _, opt := range opts {
+				opt(req)
+			}
+		}
+		return req
+	}
+	return newTypedCursor[Comment](ctx, s.client, req_fn)
+}
+
+// IterateFrom resumes iterating at a position saved with Cursor.Position.
+func (s *CommentService) IterateFrom(pos CursorPosition) (*CommentCursor, error) {
+	return s.IterateFromContext(context.Background(), pos)
+}
+
+func (s *CommentService) IterateFromContext(ctx context.Context, pos CursorPosition) (*CommentCursor, error) {
+	req_fn := func(ctx context.Context) (req *http.Request) {
+		req, _ = s.client.NewRequestContext(ctx, "GET", s.path, nil)
+		return req
+	}
+	return resumeTypedCursor[Comment](ctx, s.client, req_fn, pos)
+}
+
+func (s *CommentService) Get(commentId int) (*Comment, *http.Response, error) {
+	return s.GetContext(context.Background(), commentId)
+}
+
+func (s *CommentService) GetContext(ctx context.Context, commentId int) (*Comment, *http.Response, error) {
+	u := fmt.Sprintf("%v/%v", s.path, commentId)
+	req, err := s.client.NewRequestContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	var comment Comment
+	resp, err := s.client.Do(req, &comment)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &comment, resp, err
+}
+
+// Create posts a new comment. CommitIdentifier and CommitType must be set
+// together, marking the comment as originating from a VCS commit.
+func (s *CommentService) Create(comment *Comment) (*Comment, *http.Response, error) {
+	return s.CreateContext(context.Background(), comment)
+}
+
+func (s *CommentService) CreateContext(ctx context.Context, comment *Comment) (*Comment, *http.Response, error) {
+	if comment.CommitIdentifier != "" && comment.CommitType == "" {
+		return nil, nil, &ErrFieldNotSet{"commit_type"}
+	}
+	if comment.CommitType != "" && comment.CommitIdentifier == "" {
+		return nil, nil, &ErrFieldNotSet{"commit_identifier"}
+	}
+
+	req, err := s.client.NewRequestContext(ctx, "POST", s.path, comment)
+	if err != nil {
+		return nil, nil, err
+	}
+	var newComment Comment
+	resp, err := s.client.Do(req, &newComment)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &newComment, resp, err
+}
+
+func (s *CommentService) Update(commentId int, comment *Comment) (*Comment, *http.Response, error) {
+	return s.UpdateContext(context.Background(), commentId, comment)
+}
+
+func (s *CommentService) UpdateContext(ctx context.Context, commentId int, comment *Comment) (*Comment, *http.Response, error) {
+	u := fmt.Sprintf("%v/%v", s.path, commentId)
+	req, err := s.client.NewRequestContext(ctx, "PUT", u, comment)
+	if err != nil {
+		return nil, nil, err
+	}
+	var bodyComment Comment
+	resp, err := s.client.Do(req, &bodyComment)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &bodyComment, resp, err
+}
+
+func (s *CommentService) Delete(commentId int) (*http.Response, error) {
+	return s.DeleteContext(context.Background(), commentId)
+}
+
+func (s *CommentService) DeleteContext(ctx context.Context, commentId int) (*http.Response, error) {
+	u := fmt.Sprintf("%v/%v", s.path, commentId)
+	req, err := s.client.NewRequestContext(ctx, "DELETE", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.client.Do(req, nil)
+}
@@ -0,0 +1,193 @@
+// Copyright (C) 2015 Scott Devoid
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package pivotal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// ErrConflict is returned by UpdateFrom when the story was changed on the
+// server after the original was read.
+type ErrConflict struct {
+	StoryId  int
+	Expected *time.Time
+	Actual   *time.Time
+
+	// Current is the story as it is on the server now.
+	Current *Story
+}
+
+func (err *ErrConflict) Error() string {
+	return fmt.Sprintf("story %d was modified at %v, after it was read at %v",
+		err.StoryId, formatTime(err.Actual), formatTime(err.Expected))
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "unknown time"
+	}
+	return t.Format(time.RFC3339)
+}
+
+type updateOptions struct {
+	ifUnmodified bool
+}
+
+// UpdateOption configures UpdateFrom.
+type UpdateOption func(*updateOptions)
+
+// IfUnmodified makes UpdateFrom re-fetch the story first and fail with an
+// *ErrConflict if its UpdatedAt differs from the original's. This narrows
+// but does not close the window for lost updates, as Tracker offers no
+// conditional PUT.
+func IfUnmodified() UpdateOption {
+	return func(o *updateOptions) {
+		o.ifUnmodified = true
+	}
+}
+
+// UpdateFrom sends only the fields that differ between original and
+// modified, so that concurrent edits to other fields are preserved. When
+// nothing changed, no request is made and original is returned.
+func (service *StoryService) UpdateFrom(original, modified *Story, opts ...UpdateOption) (*Story, *http.Response, error) {
+	return service.UpdateFromContext(context.Background(), original, modified, opts...)
+}
+
+func (service *StoryService) UpdateFromContext(ctx context.Context, original, modified *Story, opts ...UpdateOption) (*Story, *http.Response, error) {
+	if original.Id == 0 {
+		return nil, nil, &ErrFieldNotSet{"id"}
+	}
+	var o updateOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	update := DiffStories(original, modified)
+	if update.IsEmpty() {
+		return original, nil, nil
+	}
+
+	if o.ifUnmodified {
+		current, resp, err := service.GetContext(ctx, original.Id)
+		if err != nil {
+			return nil, resp, err
+		}
+		if original.UpdatedAt == nil || !timesEqual(original.UpdatedAt, current.UpdatedAt) {
+			return nil, resp, &ErrConflict{
+				StoryId:  original.Id,
+				Expected: original.UpdatedAt,
+				Actual:   current.UpdatedAt,
+				Current:  current,
+			}
+		}
+	}
+
+	return service.EditContext(ctx, original.Id, update)
+}
+
+// DiffStories returns the update turning original into modified. Fields
+// Tracker allows to clear, such as the description, estimate, deadline and
+// ID lists, are cleared when modified no longer sets them. Required fields
+// left blank in modified, like the name, type, state, requester and
+// acceptance time, are treated as unchanged.
+func DiffStories(original, modified *Story) StoryUpdate {
+	var u StoryUpdate
+	if modified.Name != "" && original.Name != modified.Name {
+		u.Name = Set(modified.Name)
+	}
+	if original.Description != modified.Description {
+		u.Description = Set(modified.Description)
+	}
+	if modified.Type != "" && original.Type != modified.Type {
+		u.Type = Set(modified.Type)
+	}
+	if modified.State != "" && original.State != modified.State {
+		u.State = Set(modified.State)
+	}
+	u.Estimate = diffPtr(original.Estimate, modified.Estimate, func(a, b float64) bool { return a == b })
+	if modified.AcceptedAt != nil {
+		u.AcceptedAt = diffPtr(original.AcceptedAt, modified.AcceptedAt, time.Time.Equal)
+	}
+	u.Deadline = diffPtr(original.Deadline, modified.Deadline, time.Time.Equal)
+	if modified.RequestedById != 0 && original.RequestedById != modified.RequestedById {
+		u.RequestedById = Set(modified.RequestedById)
+	}
+	u.OwnerIds = diffIds(original.OwnerIds, modified.OwnerIds)
+	u.FollowerIds = diffIds(original.FollowerIds, modified.FollowerIds)
+	if !labelsEqual(original.Labels, modified.Labels) {
+		labels := []*Label{}
+		if modified.Labels != nil {
+			labels = *modified.Labels
+		}
+		u.Labels = Set(labels)
+	} else {
+		u.LabelIds = diffIds(original.LabelIds, modified.LabelIds)
+	}
+	if original.IntegrationId != modified.IntegrationId {
+		u.IntegrationId = Set(modified.IntegrationId)
+	}
+	if original.ExternalId != modified.ExternalId {
+		u.ExternalId = Set(modified.ExternalId)
+	}
+	return u
+}
+
+func diffPtr[T any](a, b *T, equal func(T, T) bool) Nullable[T] {
+	switch {
+	case a == nil && b == nil:
+		return Nullable[T]{}
+	case b == nil:
+		return Null[T]()
+	case a == nil || !equal(*a, *b):
+		return Set(*b)
+	}
+	return Nullable[T]{}
+}
+
+// diffIds compares ID lists, where a nil list is the same as an empty one.
+// A cleared list is sent as [], which Tracker expects for array fields.
+func diffIds(a, b *[]int) Nullable[[]int] {
+	var av, bv []int
+	if a != nil {
+		av = *a
+	}
+	if b != nil {
+		bv = *b
+	}
+	if slices.Equal(av, bv) {
+		return Nullable[[]int]{}
+	}
+	if bv == nil {
+		bv = []int{}
+	}
+	return Set(bv)
+}
+
+func labelsEqual(a, b *[]*Label) bool {
+	var av, bv []*Label
+	if a != nil {
+		av = *a
+	}
+	if b != nil {
+		bv = *b
+	}
+	return slices.EqualFunc(av, bv, func(x, y *Label) bool {
+		if x == nil || y == nil {
+			return x == y
+		}
+		return x.Id == y.Id && x.Name == y.Name
+	})
+}
+
+func timesEqual(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,50 @@
+// Copyright (C) 2015 Scott Devoid
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package pivotal_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/salsita/go-pivotaltracker/v5/pivotal"
+)
+
+func TestDiffStories(t *testing.T) {
+	estimate := 2.0
+	tests := []struct {
+		name               string
+		original, modified pivotal.Story
+		want               string
+	}{
+		{
+			name:     "blank required fields are unchanged",
+			original: pivotal.Story{Name: "a", Type: pivotal.StoryTypeBug, State: pivotal.StoryStateStarted, RequestedById: 1},
+			modified: pivotal.Story{Name: "a"},
+			want:     `{}`,
+		},
+		{
+			name:     "changed fields are sent",
+			original: pivotal.Story{Name: "a", State: pivotal.StoryStateStarted},
+			modified: pivotal.Story{Name: "b", State: pivotal.StoryStateFinished},
+			want:     `{"name":"b","current_state":"finished"}`,
+		},
+		{
+			name:     "clearable fields are cleared",
+			original: pivotal.Story{Name: "a", Description: "d", Estimate: &estimate, OwnerIds: &[]int{1}},
+			modified: pivotal.Story{Name: "a"},
+			want:     `{"description":"","estimate":null,"owner_ids":[]}`,
+		},
+	}
+	for _, tt := range tests {
+		u := pivotal.DiffStories(&tt.original, &tt.modified)
+		b, err := json.Marshal(u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, b, tt.want)
+		}
+	}
+}
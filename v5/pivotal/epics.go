@@ -5,6 +5,7 @@
 package pivotal
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -34,6 +35,30 @@ type EpicRequest struct {
 	BeforeId    int        `json:"before_id,omitempty"`
 }
 
+// EpicUpdate is a partial epic update. Unset fields are left alone,
+// fields set to Null are cleared.
+type EpicUpdate struct {
+	Name        Nullable[string] `json:"name,omitzero"`
+	LabelId     Nullable[int]    `json:"label_id,omitzero"`
+	Description Nullable[string] `json:"description,omitzero"`
+	FollowerIds Nullable[[]int]  `json:"follower_ids,omitzero"`
+	AfterId     Nullable[int]    `json:"after_id,omitzero"`
+	BeforeId    Nullable[int]    `json:"before_id,omitzero"`
+}
+
+func (u EpicUpdate) MarshalJSON() ([]byte, error) {
+	return marshalUpdate(u)
+}
+
+// request returns the values set by the update, for validation.
+func (u *EpicUpdate) request() *EpicRequest {
+	r := &EpicRequest{}
+	r.Name, _ = u.Name.Get()
+	r.AfterId, _ = u.AfterId.Get()
+	r.BeforeId, _ = u.BeforeId.Get()
+	return r
+}
+
 type EpicService struct {
 	*Client
 	projectId string
@@ -44,8 +69,12 @@ func newEpicService(client *Client, projectId string) *EpicService {
 }
 
 func (s *EpicService) List(opts ...RequestOption) ([]*Epic, *http.Response, error) {
+	return s.ListContext(context.Background(), opts...)
+}
+
+func (s *EpicService) ListContext(ctx context.Context, opts ...RequestOption) ([]*Epic, *http.Response, error) {
 	u := fmt.Sprintf("projects/%v/epics", s.projectId)
-	req, err := s.NewRequest("GET", u, nil)
+	req, err := s.NewRequestContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -60,9 +89,47 @@ func (s *EpicService) List(opts ...RequestOption) ([]*Epic, *http.Response, erro
 	return epics, resp, err
 }
 
+type EpicCursor = Cursor[Epic]
+
+func (s *EpicService) Iterate(opts ...RequestOption) (*EpicCursor, error) {
+	return s.IterateContext(context.Background(), opts...)
+}
+
+func (s *EpicService) IterateContext(ctx context.Context, opts ...RequestOption) (*EpicCursor, error) {
+	u := fmt.Sprintf("projects/%v/epics", s.projectId)
+	req_fn := func(ctx context.Context) (req *http.Request) {
+		req, _ = s.NewRequestContext(ctx, "GET", u, nil)
+		if req != nil {
+			for _, opt := range opts {
+				opt(req)
+			}
+		}
+		return req
+	}
+	return newTypedCursor[Epic](ctx, s.Client, req_fn)
+}
+
+// IterateFrom resumes iterating at a position saved with Cursor.Position.
+func (s *EpicService) IterateFrom(pos CursorPosition) (*EpicCursor, error) {
+	return s.IterateFromContext(context.Background(), pos)
+}
+
+func (s *EpicService) IterateFromContext(ctx context.Context, pos CursorPosition) (*EpicCursor, error) {
+	u := fmt.Sprintf("projects/%v/epics", s.projectId)
+	req_fn := func(ctx context.Context) (req *http.Request) {
+		req, _ = s.NewRequestContext(ctx, "GET", u, nil)
+		return req
+	}
+	return resumeTypedCursor[Epic](ctx, s.Client, req_fn, pos)
+}
+
 func (s *EpicService) Get(id int) (*Epic, *http.Response, error) {
+	return s.GetContext(context.Background(), id)
+}
+
+func (s *EpicService) GetContext(ctx context.Context, id int) (*Epic, *http.Response, error) {
 	u := fmt.Sprintf("projects/%v/epics/%v", s.projectId, id)
-	req, err := s.NewRequest("GET", u, nil)
+	req, err := s.NewRequestContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -75,12 +142,21 @@ func (s *EpicService) Get(id int) (*Epic, *http.Response, error) {
 }
 
 func (s *EpicService) Add(epic EpicRequest) (*Epic, *http.Response, error) {
+	return s.AddContext(context.Background(), epic)
+}
+
+func (s *EpicService) AddContext(ctx context.Context, epic EpicRequest) (*Epic, *http.Response, error) {
 	project := s.projectId
 	if epic.ProjectId != 0 && strconv.Itoa(epic.ProjectId) != project {
 		project = strconv.Itoa(epic.ProjectId)
 	}
+	if s.validate {
+		if err := epic.Validate(); err != nil {
+			return nil, nil, err
+		}
+	}
 	u := fmt.Sprintf("projects/%v/epics", project)
-	req, err := s.NewRequest("POST", u, epic)
+	req, err := s.NewRequestContext(ctx, "POST", u, epic)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -93,12 +169,21 @@ func (s *EpicService) Add(epic EpicRequest) (*Epic, *http.Response, error) {
 }
 
 func (s *EpicService) Update(epicId int, epic EpicRequest) (*Epic, *http.Response, error) {
+	return s.UpdateContext(context.Background(), epicId, epic)
+}
+
+func (s *EpicService) UpdateContext(ctx context.Context, epicId int, epic EpicRequest) (*Epic, *http.Response, error) {
 	project := s.projectId
 	if epic.ProjectId != 0 && strconv.Itoa(epic.ProjectId) != project {
 		project = strconv.Itoa(epic.ProjectId)
 	}
+	if s.validate {
+		if err := epic.Validate(); err != nil {
+			return nil, nil, err
+		}
+	}
 	u := fmt.Sprintf("projects/%v/epics/%v", project, epicId)
-	req, err := s.NewRequest("PUT", u, epic)
+	req, err := s.NewRequestContext(ctx, "PUT", u, epic)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -110,11 +195,45 @@ func (s *EpicService) Update(epicId int, epic EpicRequest) (*Epic, *http.Respons
 	return e, resp, err
 }
 
+// Edit applies a partial update, which unlike Update can clear fields.
+func (s *EpicService) Edit(epicId int, update EpicUpdate) (*Epic, *http.Response, error) {
+	return s.EditContext(context.Background(), epicId, update)
+}
+
+func (s *EpicService) EditContext(ctx context.Context, epicId int, update EpicUpdate) (*Epic, *http.Response, error) {
+	if s.validate {
+		if err := update.request().Validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+	u := fmt.Sprintf("projects/%v/epics/%v", s.projectId, epicId)
+	req, err := s.NewRequestContext(ctx, "PUT", u, update)
+	if err != nil {
+		return nil, nil, err
+	}
+	var epic Epic
+	resp, err := s.Do(req, &epic)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &epic, resp, err
+}
+
 func (s *EpicService) Delete(epicId int) (resp *http.Response, err error) {
+	return s.DeleteContext(context.Background(), epicId)
+}
+
+func (s *EpicService) DeleteContext(ctx context.Context, epicId int) (resp *http.Response, err error) {
 	u := fmt.Sprintf("projects/%v/epics/%v", s.projectId, epicId)
-	req, err := s.NewRequest("DELETE", u, nil)
+	req, err := s.NewRequestContext(ctx, "DELETE", u, nil)
 	if err != nil {
 		return
 	}
 	return s.Do(req, nil)
 }
+
+// Comments returns the comment service of the given epic.
+func (s *EpicService) Comments(epicId int) *CommentService {
+	u := fmt.Sprintf("projects/%v/epics/%v", s.projectId, epicId)
+	return newCommentService(s.Client, u)
+}
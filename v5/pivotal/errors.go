@@ -5,37 +5,134 @@
 package pivotal
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+)
+
+// Sentinel errors to be used with errors.Is. Errors returned by the API
+// match the sentinel for their status code or Tracker error code.
+var (
+	ErrNotFound         = errors.New("resource not found")
+	ErrUnauthorized     = errors.New("authentication failed")
+	ErrForbidden        = errors.New("operation not permitted")
+	ErrRateLimited      = errors.New("rate limit exceeded")
+	ErrValidationFailed = errors.New("validation failed")
 )
 
 // ErrAPI ----------------------------------------------------------------------
 
 type Error struct {
-	Code             string `json:"code"`
-	Error            string `json:"error"`
-	Requirement      string `json:"requirement"`
-	GeneralProblem   string `json:"general_problem"`
-	PossibleFix      string `json:"possible_fix"`
-	ValidationErrors []struct {
-		Field   string `json:"field"`
-		Problem string `json:"problem"`
-	} `json:"validation_errors"`
+	Code             string         `json:"code"`
+	Error            string         `json:"error"`
+	Requirement      string         `json:"requirement"`
+	GeneralProblem   string         `json:"general_problem"`
+	PossibleFix      string         `json:"possible_fix"`
+	ValidationErrors []FieldProblem `json:"validation_errors"`
+}
+
+// FieldProblem describes why the value of a single field was rejected.
+type FieldProblem struct {
+	Field   string `json:"field"`
+	Problem string `json:"problem"`
 }
 
 type ErrAPI struct {
 	Response *http.Response
 	Err      *Error
+
+	// Body is the raw response body, kept in particular when it could
+	// not be decoded into Err.
+	Body []byte
+
+	// Attempts is the number of times the request was sent.
+	Attempts int
 }
 
 func (err *ErrAPI) Error() string {
-	req := err.Response.Request
-	return fmt.Sprintf(
+	method, url := "", ""
+	if req := err.Response.Request; req != nil {
+		method, url = req.Method, req.URL.String()
+	}
+	msg := fmt.Sprintf(
 		"%v %v -> %v (error = %+v)",
-		req.Method,
-		req.URL,
+		method,
+		url,
 		err.Response.Status,
 		err.Err)
+	if err.Attempts > 1 {
+		msg += fmt.Sprintf(" after %d attempts", err.Attempts)
+	}
+	return msg
+}
+
+// Code returns the Tracker error code, e.g. "unfound_resource".
+func (err *ErrAPI) Code() string {
+	if err.Err == nil {
+		return ""
+	}
+	return err.Err.Code
+}
+
+// Is matches the sentinel errors of this package.
+func (err *ErrAPI) Is(target error) bool {
+	status, code := err.Response.StatusCode, err.Code()
+	switch target {
+	case ErrNotFound:
+		return status == http.StatusNotFound ||
+			code == "unfound_resource" || code == "route_not_found"
+	case ErrUnauthorized:
+		return status == http.StatusUnauthorized ||
+			code == "unauthenticated" || code == "invalid_authentication"
+	case ErrForbidden:
+		return status == http.StatusForbidden && !err.Is(ErrUnauthorized) ||
+			code == "unauthorized_operation"
+	case ErrRateLimited:
+		return status == http.StatusTooManyRequests
+	case ErrValidationFailed:
+		return code == "invalid_parameter" ||
+			err.Err != nil && len(err.Err.ValidationErrors) != 0
+	}
+	return false
+}
+
+// Unwrap exposes the per-field problems reported by Tracker as a
+// *ValidationError, so they can be retrieved with errors.As.
+func (err *ErrAPI) Unwrap() error {
+	if err.Err == nil || len(err.Err.ValidationErrors) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: err.Err.ValidationErrors}
+}
+
+// ValidationError ------------------------------------------------------------
+
+// ValidationError lists the fields that were rejected.
+type ValidationError struct {
+	Problems []FieldProblem
+}
+
+func (err *ValidationError) Error() string {
+	msgs := make([]string, len(err.Problems))
+	for i, p := range err.Problems {
+		msgs[i] = fmt.Sprintf("%s: %s", p.Field, p.Problem)
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+func (err *ValidationError) Is(target error) bool {
+	return target == ErrValidationFailed
+}
+
+// Problem returns the problem reported for field, if any.
+func (err *ValidationError) Problem(field string) (string, bool) {
+	for _, p := range err.Problems {
+		if p.Field == field {
+			return p.Problem, true
+		}
+	}
+	return "", false
 }
 
 // ErrFieldNotSet --------------------------------------------------------------
@@ -45,5 +142,14 @@ type ErrFieldNotSet struct {
 }
 
 func (err *ErrFieldNotSet) Error() string {
-	return fmt.Sprintf("Required field '%s' is not set")
+	return fmt.Sprintf("Required field '%s' is not set", err.fieldName)
+}
+
+// FieldName returns the name of the missing field.
+func (err *ErrFieldNotSet) FieldName() string {
+	return err.fieldName
+}
+
+func (err *ErrFieldNotSet) Is(target error) bool {
+	return target == ErrValidationFailed
 }
@@ -7,6 +7,7 @@ package pivotal
 import (
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -50,25 +51,27 @@ func BeforeStory(id int) RequestOption {
 
 func AcceptedBefore(t *time.Time) RequestOption {
 	return func(r *http.Request) {
-		urlAddParam(r, "accepted_before", t.String())
+		urlAddParam(r, "accepted_before", t.Format(time.RFC3339))
 	}
 }
 
 func CreatedBefore(t *time.Time) RequestOption {
 	return func(r *http.Request) {
-		urlAddParam(r, "created_before", t.String())
+		urlAddParam(r, "created_before", t.Format(time.RFC3339))
 	}
 }
 
 func CreatedAfter(t *time.Time) RequestOption {
 	return func(r *http.Request) {
-		urlAddParam(r, "created_after", t.String())
+		urlAddParam(r, "created_after", t.Format(time.RFC3339))
 	}
 }
 
+// Filter restricts a story listing to stories matching a Tracker search
+// string. Use FilterQuery to build one.
 func Filter(s string) RequestOption {
 	return func(r *http.Request) {
-		urlAddParam(r, "query", s)
+		urlAddParam(r, "filter", s)
 	}
 }
 
@@ -78,6 +81,89 @@ func WithScope(scope string) RequestOption {
 	}
 }
 
+func OccurredBefore(t time.Time) RequestOption {
+	return func(r *http.Request) {
+		urlAddParam(r, "occurred_before", t.Format(time.RFC3339))
+	}
+}
+
+func OccurredAfter(t time.Time) RequestOption {
+	return func(r *http.Request) {
+		urlAddParam(r, "occurred_after", t.Format(time.RFC3339))
+	}
+}
+
+func SinceVersion(version int) RequestOption {
+	return func(r *http.Request) {
+		urlAddParam(r, "since_version", strconv.Itoa(version))
+	}
+}
+
+// Fields selects the attributes Tracker returns, e.g. Fields("id", "name")
+// to trim a listing. Nested resources can be narrowed down as well, e.g.
+// "tasks(description,complete)". Repeated Fields options are merged.
+func Fields(fields ...string) RequestOption {
+	return func(r *http.Request) {
+		query := r.URL.Query()
+		merged := splitFields(query.Get("fields"))
+		for _, f := range fields {
+			for _, f := range splitFields(f) {
+				if !contains(merged, f) {
+					merged = append(merged, f)
+				}
+			}
+		}
+		query.Set("fields", strings.Join(merged, ","))
+		r.URL.RawQuery = query.Encode()
+	}
+}
+
+// IncludeTasks adds the tasks of each story to the default fields.
+func IncludeTasks() RequestOption {
+	return Fields(":default", "tasks")
+}
+
+// IncludeOwners adds the owners of each story to the default fields.
+func IncludeOwners() RequestOption {
+	return Fields(":default", "owners")
+}
+
+// IncludeComments adds the comments of each story to the default fields.
+func IncludeComments() RequestOption {
+	return Fields(":default", "comments")
+}
+
+// IncludeLabels adds the labels of each story to the default fields.
+func IncludeLabels() RequestOption {
+	return Fields(":default", "labels")
+}
+
+// splitFields splits a fields value at the commas that are not nested in
+// parentheses.
+func splitFields(s string) []string {
+	var fields []string
+	depth, start := 0, 0
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				if f := strings.TrimSpace(s[start:i]); f != "" {
+					fields = append(fields, f)
+				}
+				start = i + 1
+			}
+		}
+	}
+	if f := strings.TrimSpace(s[start:]); f != "" {
+		fields = append(fields, f)
+	}
+	return fields
+}
+
 func urlAddParam(r *http.Request, k, v string) {
 	query := r.URL.Query()
 	query.Add(k, v)
@@ -5,10 +5,10 @@
 package pivotal
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
-	"sync"
 	"time"
 )
 
@@ -38,6 +38,18 @@ type IterationOverrideRequest struct {
 	TeamStrength    float64 `json:"team_strength,omitempty"`
 }
 
+// IterationOverrideUpdate is a partial iteration override. Setting
+// Length or TeamStrength to Null resets it to the project default.
+type IterationOverrideUpdate struct {
+	IterationNumber int               `json:"iteration_number,omitempty"`
+	Length          Nullable[int]     `json:"length,omitzero"`
+	TeamStrength    Nullable[float64] `json:"team_strength,omitzero"`
+}
+
+func (u IterationOverrideUpdate) MarshalJSON() ([]byte, error) {
+	return marshalUpdate(u)
+}
+
 type IterationService struct {
 	*Client
 	projectId string
@@ -48,11 +60,16 @@ func newIterationService(client *Client, projectId string) *IterationService {
 }
 
 func (s *IterationService) List(opts ...RequestOption) ([]*Iteration, *http.Response, error) {
-	req_fn := func() (req *http.Request) {
-		req, _ = s.setupReq(opts...)
+	return s.ListContext(context.Background(), opts...)
+}
+
+// ListContext fetches every page of iterations, binding each request to ctx.
+func (s *IterationService) ListContext(ctx context.Context, opts ...RequestOption) ([]*Iteration, *http.Response, error) {
+	req_fn := func(ctx context.Context) (req *http.Request) {
+		req, _ = s.setupReq(ctx, opts...)
 		return req
 	}
-	cc, err := newCursor((s).Client, req_fn)
+	cc, err := newCursor(ctx, (s).Client, req_fn)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -75,44 +92,42 @@ func (s *IterationService) List(opts ...RequestOption) ([]*Iteration, *http.Resp
 	return iterations, resp, err
 }
 
-type IterationCursor struct {
-	*cursor
-	buff []*Iteration
-	lock *sync.Mutex
+type IterationCursor = Cursor[Iteration]
+
+func (s *IterationService) Iterate(opts ...RequestOption) (c *IterationCursor, err error) {
+	return s.IterateContext(context.Background(), opts...)
 }
 
-func (c *IterationCursor) Next() (i *Iteration, err error) {
-	if len(c.buff) == 0 {
-		_, err = c.next(&c.buff)
-		if err != nil {
-			return
-		}
-	}
-	if len(c.buff) == 0 {
-		err = io.EOF
-	} else {
-		i, c.buff = c.buff[0], c.buff[1:]
+func (s *IterationService) IterateContext(ctx context.Context, opts ...RequestOption) (c *IterationCursor, err error) {
+	req_fn := func(ctx context.Context) (req *http.Request) {
+		req, _ = s.setupReq(ctx, opts...)
+		return req
 	}
-	return
+	return newTypedCursor[Iteration](ctx, s.Client, req_fn)
 }
 
-func (s *IterationService) Iterate(opts ...RequestOption) (c *IterationCursor, err error) {
-	req_fn := func() (req *http.Request) {
-		req, _ = s.setupReq(opts...)
+// IterateFrom resumes iterating at a position saved with Cursor.Position.
+func (s *IterationService) IterateFrom(pos CursorPosition) (*IterationCursor, error) {
+	return s.IterateFromContext(context.Background(), pos)
+}
+
+func (s *IterationService) IterateFromContext(ctx context.Context, pos CursorPosition) (*IterationCursor, error) {
+	req_fn := func(ctx context.Context) (req *http.Request) {
+		req, _ = s.setupReq(ctx)
 		return req
 	}
-	cc, err := newCursor((s).Client, req_fn)
-	return &IterationCursor{
-		cursor: cc,
-		buff:   make([]*Iteration, 0),
-		lock:   &sync.Mutex{},
-	}, err
+	return resumeTypedCursor[Iteration](ctx, s.Client, req_fn, pos)
 }
 
 func (s *IterationService) OverrideIteration(o IterationOverrideRequest) (
+	*IterationOverride, *http.Response, error) {
+	return s.OverrideIterationContext(context.Background(), o)
+}
+
+func (s *IterationService) OverrideIterationContext(ctx context.Context, o IterationOverrideRequest) (
 	*IterationOverride, *http.Response, error) {
 	u := fmt.Sprintf("projects/%v/iterations/%v", s.projectId, o.IterationNumber)
-	req, err := s.NewRequest("PUT", u, o)
+	req, err := s.NewRequestContext(ctx, "PUT", u, o)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -124,9 +139,31 @@ func (s *IterationService) OverrideIteration(o IterationOverrideRequest) (
 	return iteration, resp, err
 }
 
-func (s *IterationService) setupReq(opts ...RequestOption) (req *http.Request, err error) {
+// EditOverride applies a partial override, which unlike
+// OverrideIteration can reset values.
+func (s *IterationService) EditOverride(o IterationOverrideUpdate) (
+	*IterationOverride, *http.Response, error) {
+	return s.EditOverrideContext(context.Background(), o)
+}
+
+func (s *IterationService) EditOverrideContext(ctx context.Context, o IterationOverrideUpdate) (
+	*IterationOverride, *http.Response, error) {
+	u := fmt.Sprintf("projects/%v/iterations/%v", s.projectId, o.IterationNumber)
+	req, err := s.NewRequestContext(ctx, "PUT", u, o)
+	if err != nil {
+		return nil, nil, err
+	}
+	var override IterationOverride
+	resp, err := s.Do(req, &override)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &override, resp, err
+}
+
+func (s *IterationService) setupReq(ctx context.Context, opts ...RequestOption) (req *http.Request, err error) {
 	u := fmt.Sprintf("projects/%v/iterations", s.projectId)
-	req, err = s.NewRequest("GET", u, nil)
+	req, err = s.NewRequestContext(ctx, "GET", u, nil)
 	if err != nil {
 		return
 	}
@@ -5,6 +5,7 @@
 package pivotal
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"time"
@@ -29,8 +30,12 @@ func newLabelService(client *Client, projectId string) *LabelService {
 }
 
 func (s *LabelService) List() (labels []*Label, resp *http.Response, err error) {
+	return s.ListContext(context.Background())
+}
+
+func (s *LabelService) ListContext(ctx context.Context) (labels []*Label, resp *http.Response, err error) {
 	u := fmt.Sprintf("projects/%s/labels", s.projectId)
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequestContext(ctx, "GET", u, nil)
 	if err != nil {
 		return
 	}
@@ -41,10 +46,48 @@ func (s *LabelService) List() (labels []*Label, resp *http.Response, err error)
 	return
 }
 
+type LabelCursor = Cursor[Label]
+
+func (s *LabelService) Iterate(opts ...RequestOption) (*LabelCursor, error) {
+	return s.IterateContext(context.Background(), opts...)
+}
+
+func (s *LabelService) IterateContext(ctx context.Context, opts ...RequestOption) (*LabelCursor, error) {
+	u := fmt.Sprintf("projects/%s/labels", s.projectId)
+	req_fn := func(ctx context.Context) (req *http.Request) {
+		req, _ = s.client.NewRequestContext(ctx, "GET", u, nil)
+		if req != nil {
+			for _, opt := range opts {
+				opt(req)
+			}
+		}
+		return req
+	}
+	return newTypedCursor[Label](ctx, s.client, req_fn)
+}
+
+// IterateFrom resumes iterating at a position saved with Cursor.Position.
+func (s *LabelService) IterateFrom(pos CursorPosition) (*LabelCursor, error) {
+	return s.IterateFromContext(context.Background(), pos)
+}
+
+func (s *LabelService) IterateFromContext(ctx context.Context, pos CursorPosition) (*LabelCursor, error) {
+	u := fmt.Sprintf("projects/%s/labels", s.projectId)
+	req_fn := func(ctx context.Context) (req *http.Request) {
+		req, _ = s.client.NewRequestContext(ctx, "GET", u, nil)
+		return req
+	}
+	return resumeTypedCursor[Label](ctx, s.client, req_fn, pos)
+}
+
 func (s *LabelService) Create(name string) (label *Label, resp *http.Response, err error) {
+	return s.CreateContext(context.Background(), name)
+}
+
+func (s *LabelService) CreateContext(ctx context.Context, name string) (label *Label, resp *http.Response, err error) {
 	l := Label{Name: name}
 	u := fmt.Sprintf("projects/%s/labels", s.projectId)
-	req, err := s.client.NewRequest("POST", u, l)
+	req, err := s.client.NewRequestContext(ctx, "POST", u, l)
 	if err != nil {
 		return
 	}
@@ -56,8 +99,12 @@ func (s *LabelService) Create(name string) (label *Label, resp *http.Response, e
 }
 
 func (s *LabelService) Get(id int) (label *Label, resp *http.Response, err error) {
+	return s.GetContext(context.Background(), id)
+}
+
+func (s *LabelService) GetContext(ctx context.Context, id int) (label *Label, resp *http.Response, err error) {
 	u := fmt.Sprintf("projects/%s/labels/%d", s.projectId, id)
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequestContext(ctx, "GET", u, nil)
 	if err != nil {
 		return
 	}
@@ -69,10 +116,15 @@ func (s *LabelService) Get(id int) (label *Label, resp *http.Response, err error
 }
 
 func (s *LabelService) Rename(id int, newName string) (
+	label *Label, resp *http.Response, err error) {
+	return s.RenameContext(context.Background(), id, newName)
+}
+
+func (s *LabelService) RenameContext(ctx context.Context, id int, newName string) (
 	label *Label, resp *http.Response, err error) {
 	l := Label{Name: newName}
 	u := fmt.Sprintf("projects/%s/labels/%d", s.projectId, id)
-	req, err := s.client.NewRequest("PUT", u, l)
+	req, err := s.client.NewRequestContext(ctx, "PUT", u, l)
 	if err != nil {
 		return
 	}
@@ -84,8 +136,12 @@ func (s *LabelService) Rename(id int, newName string) (
 }
 
 func (s *LabelService) Delete(id int) (resp *http.Response, err error) {
+	return s.DeleteContext(context.Background(), id)
+}
+
+func (s *LabelService) DeleteContext(ctx context.Context, id int) (resp *http.Response, err error) {
 	u := fmt.Sprintf("projects/%s/labels/%d", s.projectId, id)
-	req, err := s.client.NewRequest("DELETE", u, nil)
+	req, err := s.client.NewRequestContext(ctx, "DELETE", u, nil)
 	if err != nil {
 		return
 	}
@@ -5,6 +5,7 @@
 package pivotal
 
 import (
+	"context"
 	"net/http"
 	"time"
 )
@@ -34,7 +35,11 @@ func newMeService(client *Client) *MeService {
 }
 
 func (service *MeService) Get() (*Me, *http.Response, error) {
-	req, err := service.client.NewRequest("GET", "me", nil)
+	return service.GetContext(context.Background())
+}
+
+func (service *MeService) GetContext(ctx context.Context) (*Me, *http.Response, error) {
+	req, err := service.client.NewRequestContext(ctx, "GET", "me", nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -0,0 +1,125 @@
+// Copyright (C) 2015 Scott Devoid
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package pivotal
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// Nullable is a field of an update request that can be left unset, set
+// to a value or explicitly cleared. The zero value is unset and is left
+// out of the request by the update types of this package; Null clears the
+// field. Tracker clears a scalar field
+// given a JSON null but expects an empty array for a list, so Null of a
+// slice type is sent as [].
+type Nullable[T any] struct {
+	value T
+	set   bool
+	null  bool
+}
+
+// Set returns a Nullable holding v.
+func Set[T any](v T) Nullable[T] {
+	return Nullable[T]{value: v, set: true}
+}
+
+// Null returns a Nullable that clears the field.
+func Null[T any]() Nullable[T] {
+	return Nullable[T]{set: true, null: true}
+}
+
+// IsZero reports whether n is unset, in which case it is left out.
+func (n Nullable[T]) IsZero() bool {
+	return !n.set
+}
+
+// IsNull reports whether n clears the field.
+func (n Nullable[T]) IsNull() bool {
+	return n.set && n.null
+}
+
+// Get returns the value and whether n holds one.
+func (n Nullable[T]) Get() (T, bool) {
+	return n.value, n.set && !n.null
+}
+
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if n.IsNull() && reflect.TypeFor[T]().Kind() == reflect.Slice {
+		return []byte("[]"), nil
+	}
+	if !n.set || n.null {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.value)
+}
+
+func (n *Nullable[T]) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(bytes.TrimSpace(b), []byte("null")) {
+		*n = Null[T]()
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*n = Set(v)
+	return nil
+}
+
+// ptr returns the value of n as a pointer, nil unless n holds a value.
+func (n Nullable[T]) ptr() *T {
+	if v, ok := n.Get(); ok {
+		return &v
+	}
+	return nil
+}
+
+// marshalUpdate encodes an update struct, leaving out the Nullable fields
+// that are unset and the omitempty fields that are zero. Unlike the
+// omitzero tag, it also works with Go releases before 1.24, which would
+// otherwise send every unset field as null and so clear it.
+func marshalUpdate(v interface{}) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	rt := rv.Type()
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fv := rv.Field(i)
+		if z, ok := fv.Interface().(interface{ IsZero() bool }); ok && z.IsZero() {
+			continue
+		}
+		if contains(strings.Split(opts, ","), "omitempty") && fv.IsZero() {
+			continue
+		}
+
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(fv.Interface())
+		if err != nil {
+			return nil, err
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,43 @@
+// Copyright (C) 2015 Scott Devoid
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package pivotal_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/salsita/go-pivotaltracker/v5/pivotal"
+)
+
+func TestUpdateLeavesUnsetFieldsOut(t *testing.T) {
+	tests := []struct {
+		update interface{}
+		want   string
+	}{
+		{pivotal.StoryUpdate{}, `{}`},
+		{
+			pivotal.StoryUpdate{
+				Name:     pivotal.Set("story"),
+				Estimate: pivotal.Null[float64](),
+				OwnerIds: pivotal.Null[[]int](),
+			},
+			`{"name":"story","estimate":null,"owner_ids":[]}`,
+		},
+		{&pivotal.EpicUpdate{Description: pivotal.Set("")}, `{"description":""}`},
+		{
+			pivotal.IterationOverrideUpdate{IterationNumber: 3, Length: pivotal.Null[int]()},
+			`{"iteration_number":3,"length":null}`,
+		},
+	}
+	for _, tt := range tests {
+		b, err := json.Marshal(tt.update)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != tt.want {
+			t.Errorf("got %s, want %s", b, tt.want)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright (C) 2015 Scott Devoid
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package pivotal
+
+import (
+	"net/http"
+)
+
+// ClientOption configures a Client in NewClient.
+type ClientOption func(*Client)
+
+// WithHTTPClient makes the Client send requests through hc instead of
+// http.DefaultClient. Wrap hc.Transport to install a RoundTripper chain.
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *Client) {
+		if hc != nil {
+			c.client = hc
+		}
+	}
+}
+
+// WithMiddleware appends middleware to the Client.
+func WithMiddleware(m ...Middleware) ClientOption {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, m...)
+	}
+}
+
+// WithRetryPolicy enables retrying, see SetRetryPolicy.
+func WithRetryPolicy(policy *RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// Middleware observes and may modify the traffic of a Client.
+//
+// ProcessRequest is called by Do right before every attempt to send a
+// request, on a fresh copy of it, so it sees the final URL and headers,
+// in the order the middleware was registered. ProcessResponse is called for
+// every response received by Do, before its body is decoded, in reverse
+// order. Returning an error from either aborts the call with that error.
+type Middleware interface {
+	ProcessRequest(req *http.Request) error
+	ProcessResponse(resp *http.Response) error
+}
+
+// MiddlewareFuncs adapts a pair of functions to the Middleware interface.
+// Either of them may be nil.
+type MiddlewareFuncs struct {
+	Request  func(req *http.Request) error
+	Response func(resp *http.Response) error
+}
+
+func (m MiddlewareFuncs) ProcessRequest(req *http.Request) error {
+	if m.Request == nil {
+		return nil
+	}
+	return m.Request(req)
+}
+
+func (m MiddlewareFuncs) ProcessResponse(resp *http.Response) error {
+	if m.Response == nil {
+		return nil
+	}
+	return m.Response(resp)
+}
+
+func (c *Client) processRequest(req *http.Request) error {
+	for _, m := range c.middleware {
+		if err := m.ProcessRequest(req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Client) processResponse(resp *http.Response) error {
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		if err := c.middleware[i].ProcessResponse(resp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright (C) 2015 Scott Devoid
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package pivotal_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/salsita/go-pivotaltracker/v5/pivotal"
+)
+
+func TestMiddlewareRunsOncePerAttempt(t *testing.T) {
+	var (
+		lock    sync.Mutex
+		sigs    [][]string
+		queries []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		sigs = append(sigs, r.Header.Values("X-Sig"))
+		n := len(sigs)
+		lock.Unlock()
+		if n <= 2 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, "[]")
+	}))
+	defer srv.Close()
+
+	client := pivotal.NewClient("token",
+		pivotal.WithRetryPolicy(&pivotal.RetryPolicy{MaxAttempts: 3}),
+		pivotal.WithMiddleware(pivotal.MiddlewareFuncs{
+			Request: func(req *http.Request) error {
+				req.Header.Add("X-Sig", "1")
+				queries = append(queries, req.URL.RawQuery)
+				return nil
+			},
+		}))
+	client.SetBaseURL(srv.URL + "/services/v5/")
+
+	cur, err := client.Project(1).Stories.Iterate(pivotal.WithState(pivotal.StoryStateStarted))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cur.Next(); err != io.EOF {
+		t.Fatalf("err = %v, want io.EOF", err)
+	}
+
+	if len(sigs) != 3 {
+		t.Fatalf("attempts = %d, want 3", len(sigs))
+	}
+	for i, sig := range sigs {
+		if !slices.Equal(sig, []string{"1"}) {
+			t.Errorf("attempt %d: X-Sig = %v, want [1]", i+1, sig)
+		}
+	}
+	// Middleware must see the request as sent, with options and paging.
+	for i, q := range queries {
+		if q != "limit=10&offset=0&with_state=started" {
+			t.Errorf("attempt %d: query = %q", i+1, q)
+		}
+	}
+}
+
+func TestMiddlewareLeavesRequestAlone(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "{}")
+	}))
+	defer srv.Close()
+
+	client := pivotal.NewClient("token", pivotal.WithMiddleware(pivotal.MiddlewareFuncs{
+		Request: func(req *http.Request) error {
+			req.Header.Set("X-Sig", "1")
+			return nil
+		},
+	}))
+	client.SetBaseURL(srv.URL + "/services/v5/")
+
+	req, err := client.NewRequest("GET", "me", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Do(req, nil); err != nil {
+		t.Fatal(err)
+	}
+	if sig := req.Header.Get("X-Sig"); sig != "" {
+		t.Errorf("caller's request was changed, X-Sig = %q", sig)
+	}
+}
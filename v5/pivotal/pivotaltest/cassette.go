@@ -0,0 +1,236 @@
+// Copyright (C) 2015 Scott Devoid
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package pivotaltest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"sync"
+)
+
+type CassetteMode int
+
+const (
+	// ModeRecord sends requests to the real API and records them.
+	ModeRecord CassetteMode = iota
+	// ModeReplay serves recorded responses and never touches the network.
+	ModeReplay
+	// ModeAuto replays when the fixture file exists and records otherwise.
+	ModeAuto
+)
+
+// scrubbedHeaders are never written to a fixture file.
+var scrubbedHeaders = []string{"X-Trackertoken", "Authorization", "Cookie", "Set-Cookie"}
+
+// Interaction is a recorded request/response pair.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Query  string      `json:"query,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Cassette is an http.RoundTripper that records the traffic of a Client
+// to a fixture file, or replays it from one. Install it with
+//
+//	pivotal.NewClient(token, pivotal.WithHTTPClient(cassette.HTTPClient()))
+//
+// Requests are matched on method, path and query string, where the query
+// is normalized so that parameter order does not matter. Identical
+// requests are replayed in the order they were recorded.
+type Cassette struct {
+	// Transport performs requests in record mode. It defaults to
+	// http.DefaultTransport.
+	Transport http.RoundTripper
+
+	path   string
+	replay bool
+
+	lock         sync.Mutex
+	interactions []*Interaction
+	used         []bool
+}
+
+// NewCassette creates a cassette backed by the fixture file at path.
+// In replay mode the file is loaded immediately.
+func NewCassette(path string, mode CassetteMode) (*Cassette, error) {
+	c := &Cassette{path: path}
+	if mode == ModeAuto {
+		mode = ModeRecord
+		if _, err := os.Stat(path); err == nil {
+			mode = ModeReplay
+		}
+	}
+	if mode == ModeReplay {
+		c.replay = true
+		if err := c.load(); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Recording reports whether the cassette is in record mode.
+func (c *Cassette) Recording() bool {
+	return !c.replay
+}
+
+// HTTPClient returns an http.Client using the cassette as transport.
+func (c *Cassette) HTTPClient() *http.Client {
+	return &http.Client{Transport: c}
+}
+
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	if c.replay {
+		return c.play(req)
+	}
+	return c.record(req)
+}
+
+// Save writes the recorded interactions to the fixture file. It does
+// nothing in replay mode.
+func (c *Cassette) Save() error {
+	if c.replay {
+		return nil
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	b, err := json.MarshalIndent(c.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, append(b, '\n'), 0644)
+}
+
+func (c *Cassette) load() error {
+	b, err := os.ReadFile(c.path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, &c.interactions); err != nil {
+		return fmt.Errorf("pivotaltest: parsing %s: %v", c.path, err)
+	}
+	c.used = make([]bool, len(c.interactions))
+	return nil
+}
+
+func (c *Cassette) record(req *http.Request) (*http.Response, error) {
+	// Read a copy of the body, a RoundTripper must not consume req.Body.
+	// Requests without GetBody are recorded without their body.
+	var reqBody []byte
+	if req.Body != nil && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		reqBody, err = io.ReadAll(body)
+		body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	transport := c.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	c.lock.Lock()
+	c.interactions = append(c.interactions, &Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			Path:   req.URL.Path,
+			Query:  normalizeQuery(req.URL.Query()),
+			Header: scrub(req.Header),
+			Body:   string(reqBody),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     scrub(resp.Header),
+			Body:       string(respBody),
+		},
+	})
+	c.used = append(c.used, true)
+	c.lock.Unlock()
+	return resp, nil
+}
+
+func (c *Cassette) play(req *http.Request) (*http.Response, error) {
+	query := normalizeQuery(req.URL.Query())
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for i, in := range c.interactions {
+		r := in.Request
+		if c.used[i] || r.Method != req.Method || r.Path != req.URL.Path || r.Query != query {
+			continue
+		}
+		c.used[i] = true
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		header := in.Response.Header.Clone()
+		if header == nil {
+			header = make(http.Header)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader([]byte(in.Response.Body))),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("pivotaltest: no recorded interaction for %s %s?%s in %s",
+		req.Method, req.URL.Path, query, c.path)
+}
+
+// normalizeQuery encodes the query with keys and values sorted.
+func normalizeQuery(q url.Values) string {
+	for _, vs := range q {
+		sort.Strings(vs)
+	}
+	return q.Encode()
+}
+
+func scrub(h http.Header) http.Header {
+	h = h.Clone()
+	for _, k := range scrubbedHeaders {
+		h.Del(k)
+	}
+	return h
+}
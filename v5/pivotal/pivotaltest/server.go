@@ -0,0 +1,1214 @@
+// Copyright (C) 2015 Scott Devoid
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+// Package pivotaltest provides an in-memory fake of the Pivotal Tracker
+// API for testing code built on the pivotal package.
+//
+//	srv := pivotaltest.NewServer()
+//	defer srv.Close()
+//	p := srv.AddProject(pivotal.Project{Name: "Test"})
+//	client := srv.Client()
+//	stories, _, err := client.Project(p.Id).Stories.List()
+//
+// The server keeps its state in memory for its whole lifetime, emits the
+// X-Tracker-Pagination-* headers on paginated endpoints and reports
+// failures with Tracker-shaped error bodies.
+package pivotaltest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/salsita/go-pivotaltracker/v5/pivotal"
+)
+
+// BasePath is the path prefix the fake API is served under.
+const BasePath = "/services/v5/"
+
+const (
+	defaultPageLimit = 100
+	maxPageLimit     = 500
+)
+
+// Server is a stateful, in-memory Tracker. It is safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	// Token, when set, must be sent by clients in the X-TrackerToken header.
+	Token string
+
+	// Now returns the time used for created_at and updated_at values.
+	Now func() time.Time
+
+	lock     sync.Mutex
+	lastId   int
+	me       *pivotal.Me
+	people   map[int]*pivotal.Person
+	projects map[int]*project
+}
+
+type project struct {
+	project    *pivotal.Project
+	stories    []*pivotal.Story
+	tasks      map[int][]*pivotal.Task
+	labels     []*pivotal.Label
+	epics      []*pivotal.Epic
+	iterations []*pivotal.Iteration
+	comments   map[string][]*pivotal.Comment
+}
+
+// NewServer starts a fake Tracker. Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		Now: func() time.Time {
+			return time.Now().UTC().Truncate(time.Second)
+		},
+		people:   make(map[int]*pivotal.Person),
+		projects: make(map[int]*project),
+	}
+	s.me = &pivotal.Me{
+		Id:         s.newId(),
+		Name:       "Test User",
+		Initials:   "TU",
+		Username:   "test",
+		Email:      "test@example.com",
+		ProjectIds: &[]int{},
+		TimeZone:   &pivotal.TimeZone{OlsonName: "Etc/UTC", Offset: "+00:00"},
+	}
+	s.people[s.me.Id] = &pivotal.Person{
+		Id:       s.me.Id,
+		Name:     s.me.Name,
+		Initials: s.me.Initials,
+		Username: s.me.Username,
+		Email:    s.me.Email,
+		Kind:     "person",
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// BaseURL returns the URL to pass to Client.SetBaseURL.
+func (s *Server) BaseURL() string {
+	return s.URL + BasePath
+}
+
+// Client returns a client talking to the server.
+func (s *Server) Client(opts ...pivotal.ClientOption) *pivotal.Client {
+	c := pivotal.NewClient(s.Token, opts...)
+	c.SetBaseURL(s.BaseURL())
+	return c
+}
+
+// Me returns the person the server authenticates every client as.
+func (s *Server) Me() *pivotal.Me {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return clone(s.me)
+}
+
+// AddPerson registers a person who can be referenced as story owner.
+func (s *Server) AddPerson(p pivotal.Person) *pivotal.Person {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if p.Id == 0 {
+		p.Id = s.newId()
+	}
+	p.Kind = "person"
+	s.people[p.Id] = &p
+	out := p
+	return &out
+}
+
+// AddProject stores a project, filling in the ID and defaults.
+func (s *Server) AddProject(p pivotal.Project) *pivotal.Project {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return clone(s.addProject(&p))
+}
+
+// AddStory stores a story in the given project, filling in the ID and
+// defaults. It panics if the project does not exist.
+func (s *Server) AddStory(projectId int, story pivotal.Story) *pivotal.Story {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	p := s.mustProject(projectId)
+	return clone(s.addStory(p, &story))
+}
+
+// AddLabel stores a label in the given project.
+func (s *Server) AddLabel(projectId int, label pivotal.Label) *pivotal.Label {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	p := s.mustProject(projectId)
+	return clone(s.addLabel(p, label.Name))
+}
+
+// AddEpic stores an epic in the given project.
+func (s *Server) AddEpic(projectId int, epic pivotal.Epic) *pivotal.Epic {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	p := s.mustProject(projectId)
+	return clone(s.addEpic(p, &epic))
+}
+
+// AddIteration stores an iteration in the given project. Iterations are
+// numbered consecutively when Number is not set.
+func (s *Server) AddIteration(projectId int, it pivotal.Iteration) *pivotal.Iteration {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	p := s.mustProject(projectId)
+	if it.Number == 0 {
+		it.Number = len(p.iterations) + 1
+	}
+	it.ProjectId = projectId
+	it.Kind = "iteration"
+	p.iterations = append(p.iterations, &it)
+	return clone(&it)
+}
+
+func (s *Server) mustProject(id int) *project {
+	p, ok := s.projects[id]
+	if !ok {
+		panic(fmt.Sprintf("pivotaltest: project %d does not exist", id))
+	}
+	return p
+}
+
+func (s *Server) newId() int {
+	s.lastId++
+	return s.lastId
+}
+
+// HTTP plumbing ---------------------------------------------------------------
+
+type validationError struct {
+	Field   string `json:"field"`
+	Problem string `json:"problem"`
+}
+
+type errorBody struct {
+	Kind             string            `json:"kind"`
+	Code             string            `json:"code"`
+	Error            string            `json:"error"`
+	GeneralProblem   string            `json:"general_problem,omitempty"`
+	ValidationErrors []validationError `json:"validation_errors,omitempty"`
+}
+
+// apiError is returned by handlers to produce a Tracker error response.
+type apiError struct {
+	status int
+	body   errorBody
+}
+
+func (e *apiError) Error() string {
+	return e.body.Error
+}
+
+func errNotFound() *apiError {
+	return &apiError{http.StatusNotFound, errorBody{
+		Kind:  "error",
+		Code:  "unfound_resource",
+		Error: "The object you tried to access could not be found.  It may have been removed by another user, you may be using the ID of another object type, or you may be trying to access a sub-resource at the wrong point in a tree.",
+	}}
+}
+
+func errRouteNotFound() *apiError {
+	return &apiError{http.StatusNotFound, errorBody{
+		Kind:  "error",
+		Code:  "route_not_found",
+		Error: "The path you requested has no valid endpoint.",
+	}}
+}
+
+func errInvalidParameter(field, problem string) *apiError {
+	return &apiError{http.StatusBadRequest, errorBody{
+		Kind:             "error",
+		Code:             "invalid_parameter",
+		Error:            "One or more request parameters was missing or invalid.",
+		GeneralProblem:   fmt.Sprintf("%s %s", field, problem),
+		ValidationErrors: []validationError{{Field: field, Problem: problem}},
+	}}
+}
+
+func errInvalidAuthentication() *apiError {
+	return &apiError{http.StatusForbidden, errorBody{
+		Kind:  "error",
+		Code:  "invalid_authentication",
+		Error: "Invalid authentication credentials were presented.",
+	}}
+}
+
+// noContent is returned by handlers that respond with 204 and no body.
+type noContent struct{}
+
+type request struct {
+	method string
+	parts  []string
+	query  url.Values
+	body   []byte
+	header http.Header
+}
+
+func (r *request) decode(v interface{}) *apiError {
+	if len(r.body) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(r.body, v); err != nil {
+		return &apiError{http.StatusBadRequest, errorBody{
+			Kind:  "error",
+			Code:  "invalid_parameter",
+			Error: "The request body could not be parsed: " + err.Error(),
+		}}
+	}
+	return nil
+}
+
+// has reports whether the JSON body sets the given attribute.
+func (r *request) has(key string) bool {
+	var m map[string]json.RawMessage
+	if json.Unmarshal(r.body, &m) != nil {
+		return false
+	}
+	_, ok := m[key]
+	return ok
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	v, err := s.handle(w, r)
+	if err != nil {
+		writeJSON(w, err.status, err.body)
+		return
+	}
+	if _, ok := v.(noContent); ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, http.StatusOK, v)
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) (interface{}, *apiError) {
+	if !strings.HasPrefix(r.URL.Path, BasePath) {
+		return nil, errRouteNotFound()
+	}
+	if s.Token != "" && r.Header.Get("X-TrackerToken") != s.Token {
+		return nil, errInvalidAuthentication()
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, &apiError{http.StatusBadRequest, errorBody{
+			Kind: "error", Code: "invalid_parameter", Error: err.Error(),
+		}}
+	}
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, BasePath), "/")
+	req := &request{
+		method: r.Method,
+		parts:  strings.Split(path, "/"),
+		query:  r.URL.Query(),
+		body:   body,
+		header: w.Header(),
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	v, apiErr := s.route(req)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	if _, ok := v.(noContent); ok {
+		return v, nil
+	}
+	// Encode while holding the lock, v usually points into server state.
+	b, merr := json.Marshal(v)
+	if merr != nil {
+		panic(merr)
+	}
+	return json.RawMessage(b), nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// paginate returns the window of n items selected by the limit and offset
+// parameters and sets the pagination headers accordingly.
+func paginate(r *request, n int) (lo, hi int, err *apiError) {
+	limit, offset := defaultPageLimit, 0
+	if v := r.query.Get("limit"); v != "" {
+		i, cerr := strconv.Atoi(v)
+		if cerr != nil || i < 1 || i > maxPageLimit {
+			return 0, 0, errInvalidParameter("limit", fmt.Sprintf("must be between 1 and %d", maxPageLimit))
+		}
+		limit = i
+	}
+	if v := r.query.Get("offset"); v != "" {
+		i, cerr := strconv.Atoi(v)
+		if cerr != nil || i < 0 {
+			return 0, 0, errInvalidParameter("offset", "must be a non-negative integer")
+		}
+		offset = i
+	}
+	lo, hi = offset, offset+limit
+	if lo > n {
+		lo = n
+	}
+	if hi > n {
+		hi = n
+	}
+	r.header.Set("X-Tracker-Pagination-Total", strconv.Itoa(n))
+	r.header.Set("X-Tracker-Pagination-Limit", strconv.Itoa(limit))
+	r.header.Set("X-Tracker-Pagination-Offset", strconv.Itoa(offset))
+	r.header.Set("X-Tracker-Pagination-Returned", strconv.Itoa(hi-lo))
+	return lo, hi, nil
+}
+
+func parseId(s string) (int, *apiError) {
+	id, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, errRouteNotFound()
+	}
+	return id, nil
+}
+
+// clone returns a deep copy of v, so that callers never alias server state.
+func clone[T any](v *T) *T {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	out := new(T)
+	if err := json.Unmarshal(b, out); err != nil {
+		panic(err)
+	}
+	return out
+}
+
+// Routing ---------------------------------------------------------------------
+
+func (s *Server) route(r *request) (interface{}, *apiError) {
+	parts := r.parts
+	switch {
+	case len(parts) == 1 && parts[0] == "me":
+		if r.method != "GET" {
+			return nil, errRouteNotFound()
+		}
+		return s.me, nil
+	case len(parts) == 1 && parts[0] == "aggregator":
+		if r.method != "POST" {
+			return nil, errRouteNotFound()
+		}
+		return s.aggregate(r)
+	case parts[0] == "projects":
+		return s.routeProjects(r, parts[1:])
+	}
+	return nil, errRouteNotFound()
+}
+
+// aggregate runs each GET URL of the request body and returns the
+// responses keyed by URL, as the '/aggregator' endpoint does.
+func (s *Server) aggregate(r *request) (interface{}, *apiError) {
+	var urls []string
+	if err := r.decode(&urls); err != nil {
+		return nil, err
+	}
+	out := make(map[string]json.RawMessage, len(urls))
+	for _, raw := range urls {
+		var v interface{}
+		u, perr := url.Parse(raw)
+		if perr != nil || !strings.HasPrefix(u.Path, BasePath) {
+			v = errRouteNotFound().body
+		} else {
+			path := strings.Trim(strings.TrimPrefix(u.Path, BasePath), "/")
+			sub := &request{
+				method: "GET",
+				parts:  strings.Split(path, "/"),
+				query:  u.Query(),
+				header: make(http.Header),
+			}
+			res, apiErr := s.route(sub)
+			if apiErr != nil {
+				v = apiErr.body
+			} else {
+				v = res
+			}
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			panic(err)
+		}
+		out[raw] = b
+	}
+	return out, nil
+}
+
+func (s *Server) routeProjects(r *request, parts []string) (interface{}, *apiError) {
+	if len(parts) == 0 {
+		switch r.method {
+		case "GET":
+			return s.listProjects(), nil
+		case "POST":
+			return s.createProject(r)
+		}
+		return nil, errRouteNotFound()
+	}
+
+	id, err := parseId(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	p, ok := s.projects[id]
+	if !ok {
+		return nil, errNotFound()
+	}
+
+	if len(parts) == 1 {
+		switch r.method {
+		case "GET":
+			return p.project, nil
+		case "PUT":
+			if err := r.decode(p.project); err != nil {
+				return nil, err
+			}
+			p.project.Id = id
+			p.project.UpdatedAt = s.now()
+			return p.project, nil
+		case "DELETE":
+			delete(s.projects, id)
+			s.syncProjectIds()
+			return noContent{}, nil
+		}
+		return nil, errRouteNotFound()
+	}
+
+	switch parts[1] {
+	case "stories":
+		return s.routeStories(r, p, parts[2:])
+	case "labels":
+		return s.routeLabels(r, p, parts[2:])
+	case "epics":
+		return s.routeEpics(r, p, parts[2:])
+	case "iterations":
+		return s.routeIterations(r, p, parts[2:])
+	}
+	return nil, errRouteNotFound()
+}
+
+func (s *Server) routeStories(r *request, p *project, parts []string) (interface{}, *apiError) {
+	if len(parts) == 0 {
+		switch r.method {
+		case "GET":
+			return s.listStories(r, p)
+		case "POST":
+			var story pivotal.Story
+			if err := r.decode(&story); err != nil {
+				return nil, err
+			}
+			if story.Name == "" {
+				return nil, errInvalidParameter("name", "can't be blank")
+			}
+			return s.addStory(p, &story), nil
+		}
+		return nil, errRouteNotFound()
+	}
+
+	id, err := parseId(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	i, story := p.story(id)
+	if story == nil {
+		return nil, errNotFound()
+	}
+
+	if len(parts) == 1 {
+		switch r.method {
+		case "GET":
+			return s.nested(r, p, story), nil
+		case "PUT":
+			return s.updateStory(r, p, i, story)
+		case "DELETE":
+			p.stories = append(p.stories[:i], p.stories[i+1:]...)
+			delete(p.tasks, id)
+			delete(p.comments, commentKey("stories", id))
+			return noContent{}, nil
+		}
+		return nil, errRouteNotFound()
+	}
+
+	switch parts[1] {
+	case "tasks":
+		return s.routeTasks(r, p, story, parts[2:])
+	case "comments":
+		return s.routeComments(r, p, "stories", id, parts[2:])
+	case "owners":
+		if len(parts) != 2 || r.method != "GET" {
+			return nil, errRouteNotFound()
+		}
+		return s.owners(story), nil
+	}
+	return nil, errRouteNotFound()
+}
+
+func (s *Server) owners(story *pivotal.Story) []*pivotal.Person {
+	owners := []*pivotal.Person{}
+	if story.OwnerIds != nil {
+		for _, oid := range *story.OwnerIds {
+			if person, ok := s.people[oid]; ok {
+				owners = append(owners, person)
+			}
+		}
+	}
+	return owners
+}
+
+// nested returns a copy of story carrying the tasks, owners and comments
+// requested by the fields parameter. Other field selections are ignored.
+func (s *Server) nested(r *request, p *project, story *pivotal.Story) *pivotal.Story {
+	fields := strings.Split(r.query.Get("fields"), ",")
+	has := func(name string) bool {
+		for _, f := range fields {
+			if f == name || strings.HasPrefix(f, name+"(") {
+				return true
+			}
+		}
+		return false
+	}
+	if !has("tasks") && !has("owners") && !has("comments") {
+		return story
+	}
+	out := *story
+	if has("tasks") {
+		tasks := append([]*pivotal.Task{}, p.tasks[story.Id]...)
+		out.Tasks = &tasks
+	}
+	if has("owners") {
+		owners := s.owners(story)
+		out.Owners = &owners
+	}
+	if has("comments") {
+		comments := append([]*pivotal.Comment{}, p.comments[commentKey("stories", story.Id)]...)
+		out.Comments = &comments
+	}
+	return &out
+}
+
+func (s *Server) routeTasks(r *request, p *project, story *pivotal.Story, parts []string) (interface{}, *apiError) {
+	tasks := p.tasks[story.Id]
+	if len(parts) == 0 {
+		switch r.method {
+		case "GET":
+			if tasks == nil {
+				tasks = []*pivotal.Task{}
+			}
+			return tasks, nil
+		case "POST":
+			var task pivotal.Task
+			if err := r.decode(&task); err != nil {
+				return nil, err
+			}
+			if task.Description == "" {
+				return nil, errInvalidParameter("description", "can't be blank")
+			}
+			task.Id = s.newId()
+			task.StoryId = story.Id
+			task.CreatedAt = s.now()
+			task.UpdatedAt = task.CreatedAt
+			p.tasks[story.Id] = placeTask(tasks, &task, task.Position)
+			s.syncTaskIds(p, story)
+			return &task, nil
+		}
+		return nil, errRouteNotFound()
+	}
+
+	if len(parts) != 1 {
+		return nil, errRouteNotFound()
+	}
+	id, err := parseId(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	i := -1
+	for j, t := range tasks {
+		if t.Id == id {
+			i = j
+		}
+	}
+	if i < 0 {
+		return nil, errNotFound()
+	}
+	task := tasks[i]
+
+	switch r.method {
+	case "GET":
+		return task, nil
+	case "PUT":
+		if err := r.decode(task); err != nil {
+			return nil, err
+		}
+		task.Id, task.StoryId = id, story.Id
+		task.UpdatedAt = s.now()
+		rest := append(tasks[:i:i], tasks[i+1:]...)
+		p.tasks[story.Id] = placeTask(rest, task, task.Position)
+		return task, nil
+	case "DELETE":
+		p.tasks[story.Id] = placeTask(append(tasks[:i:i], tasks[i+1:]...), nil, 0)
+		s.syncTaskIds(p, story)
+		return noContent{}, nil
+	}
+	return nil, errRouteNotFound()
+}
+
+// placeTask inserts task at the 1-based position (appending when position
+// is out of range) and renumbers all tasks.
+func placeTask(tasks []*pivotal.Task, task *pivotal.Task, position int) []*pivotal.Task {
+	out := make([]*pivotal.Task, 0, len(tasks)+1)
+	out = append(out, tasks...)
+	if task != nil {
+		if position < 1 || position > len(out) {
+			out = append(out, task)
+		} else {
+			out = append(out[:position-1], append([]*pivotal.Task{task}, out[position-1:]...)...)
+		}
+	}
+	for i, t := range out {
+		t.Position = i + 1
+	}
+	return out
+}
+
+func (s *Server) syncTaskIds(p *project, story *pivotal.Story) {
+	ids := []int{}
+	for _, t := range p.tasks[story.Id] {
+		ids = append(ids, t.Id)
+	}
+	story.TaskIds = &ids
+}
+
+func commentKey(parent string, id int) string {
+	return fmt.Sprintf("%s/%d", parent, id)
+}
+
+func (s *Server) routeComments(r *request, p *project, parent string, parentId int, parts []string) (interface{}, *apiError) {
+	key := commentKey(parent, parentId)
+	comments := p.comments[key]
+	if len(parts) == 0 {
+		switch r.method {
+		case "GET":
+			if comments == nil {
+				comments = []*pivotal.Comment{}
+			}
+			return comments, nil
+		case "POST":
+			var comment pivotal.Comment
+			if err := r.decode(&comment); err != nil {
+				return nil, err
+			}
+			if comment.Text == "" && len(comment.FileAttachmentIds) == 0 && len(comment.GoogleAttachmentIds) == 0 {
+				return nil, errInvalidParameter("text", "can't be blank")
+			}
+			comment.Id = s.newId()
+			if parent == "stories" {
+				comment.StoryId = parentId
+			} else {
+				comment.EpicId = parentId
+			}
+			if comment.PersonId == 0 {
+				comment.PersonId = s.me.Id
+			}
+			comment.CreatedAt = s.now()
+			comment.UpdatedAt = comment.CreatedAt
+			comment.Kind = "comment"
+			p.comments[key] = append(comments, &comment)
+			return &comment, nil
+		}
+		return nil, errRouteNotFound()
+	}
+
+	if len(parts) != 1 {
+		return nil, errRouteNotFound()
+	}
+	id, err := parseId(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	for i, comment := range comments {
+		if comment.Id != id {
+			continue
+		}
+		switch r.method {
+		case "GET":
+			return comment, nil
+		case "PUT":
+			if err := r.decode(comment); err != nil {
+				return nil, err
+			}
+			comment.Id = id
+			comment.UpdatedAt = s.now()
+			return comment, nil
+		case "DELETE":
+			p.comments[key] = append(comments[:i:i], comments[i+1:]...)
+			return noContent{}, nil
+		}
+		return nil, errRouteNotFound()
+	}
+	return nil, errNotFound()
+}
+
+func (s *Server) routeLabels(r *request, p *project, parts []string) (interface{}, *apiError) {
+	if len(parts) == 0 {
+		switch r.method {
+		case "GET":
+			labels := p.labels
+			if labels == nil {
+				labels = []*pivotal.Label{}
+			}
+			return labels, nil
+		case "POST":
+			var label pivotal.Label
+			if err := r.decode(&label); err != nil {
+				return nil, err
+			}
+			if label.Name == "" {
+				return nil, errInvalidParameter("name", "can't be blank")
+			}
+			if p.labelByName(label.Name) != nil {
+				return nil, errInvalidParameter("name", "has already been taken")
+			}
+			return s.addLabel(p, label.Name), nil
+		}
+		return nil, errRouteNotFound()
+	}
+
+	if len(parts) != 1 {
+		return nil, errRouteNotFound()
+	}
+	id, err := parseId(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	for i, label := range p.labels {
+		if label.Id != id {
+			continue
+		}
+		switch r.method {
+		case "GET":
+			return label, nil
+		case "PUT":
+			var l pivotal.Label
+			if err := r.decode(&l); err != nil {
+				return nil, err
+			}
+			if l.Name != "" {
+				label.Name = l.Name
+			}
+			label.UpdatedAt = s.now()
+			return label, nil
+		case "DELETE":
+			p.labels = append(p.labels[:i:i], p.labels[i+1:]...)
+			for _, story := range p.stories {
+				if story.LabelIds == nil {
+					continue
+				}
+				ids := []int{}
+				for _, lid := range *story.LabelIds {
+					if lid != id {
+						ids = append(ids, lid)
+					}
+				}
+				story.LabelIds = &ids
+				s.resolveLabels(p, story, false)
+			}
+			return noContent{}, nil
+		}
+		return nil, errRouteNotFound()
+	}
+	return nil, errNotFound()
+}
+
+func (s *Server) routeEpics(r *request, p *project, parts []string) (interface{}, *apiError) {
+	if len(parts) == 0 {
+		switch r.method {
+		case "GET":
+			epics := p.epics
+			if epics == nil {
+				epics = []*pivotal.Epic{}
+			}
+			return epics, nil
+		case "POST":
+			var epic pivotal.Epic
+			if err := r.decode(&epic); err != nil {
+				return nil, err
+			}
+			if epic.Name == "" {
+				return nil, errInvalidParameter("name", "can't be blank")
+			}
+			return s.addEpic(p, &epic), nil
+		}
+		return nil, errRouteNotFound()
+	}
+
+	id, err := parseId(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	i := -1
+	for j, e := range p.epics {
+		if e.Id == id {
+			i = j
+		}
+	}
+	if i < 0 {
+		return nil, errNotFound()
+	}
+	epic := p.epics[i]
+
+	if len(parts) > 1 {
+		if parts[1] == "comments" {
+			return s.routeComments(r, p, "epics", id, parts[2:])
+		}
+		return nil, errRouteNotFound()
+	}
+
+	switch r.method {
+	case "GET":
+		return epic, nil
+	case "PUT":
+		if err := r.decode(epic); err != nil {
+			return nil, err
+		}
+		epic.Id, epic.ProjectId = id, p.project.Id
+		epic.UpdatedAt = s.now()
+		return epic, nil
+	case "DELETE":
+		p.epics = append(p.epics[:i:i], p.epics[i+1:]...)
+		delete(p.comments, commentKey("epics", id))
+		return noContent{}, nil
+	}
+	return nil, errRouteNotFound()
+}
+
+func (s *Server) routeIterations(r *request, p *project, parts []string) (interface{}, *apiError) {
+	if len(parts) == 0 {
+		if r.method != "GET" {
+			return nil, errRouteNotFound()
+		}
+		lo, hi, err := paginate(r, len(p.iterations))
+		if err != nil {
+			return nil, err
+		}
+		return append([]*pivotal.Iteration{}, p.iterations[lo:hi]...), nil
+	}
+
+	if len(parts) != 1 || r.method != "PUT" {
+		return nil, errRouteNotFound()
+	}
+	number, err := parseId(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	for _, it := range p.iterations {
+		if it.Number != number {
+			continue
+		}
+		var o pivotal.IterationOverrideRequest
+		if err := r.decode(&o); err != nil {
+			return nil, err
+		}
+		if o.Length != 0 {
+			it.Length = o.Length
+		}
+		if o.TeamStrength != 0 {
+			it.TeamStrength = o.TeamStrength
+		}
+		return &pivotal.IterationOverride{
+			Number:       it.Number,
+			ProjectId:    p.project.Id,
+			Length:       it.Length,
+			TeamStrength: it.TeamStrength,
+			Kind:         "iteration_override",
+		}, nil
+	}
+	return nil, errNotFound()
+}
+
+// Projects --------------------------------------------------------------------
+
+func (s *Server) listProjects() []*pivotal.Project {
+	projects := []*pivotal.Project{}
+	for _, p := range s.projects {
+		projects = append(projects, p.project)
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		return projects[i].Id < projects[j].Id
+	})
+	return projects
+}
+
+func (s *Server) createProject(r *request) (interface{}, *apiError) {
+	var project pivotal.Project
+	if err := r.decode(&project); err != nil {
+		return nil, err
+	}
+	if project.Name == "" {
+		return nil, errInvalidParameter("name", "can't be blank")
+	}
+	return s.addProject(&project), nil
+}
+
+func (s *Server) addProject(proj *pivotal.Project) *pivotal.Project {
+	if proj.Id == 0 {
+		proj.Id = s.newId()
+	}
+	if proj.Version == 0 {
+		proj.Version = 1
+	}
+	if proj.IterationLength == 0 {
+		proj.IterationLength = 1
+	}
+	if proj.WeekStartDay == "" {
+		proj.WeekStartDay = "Monday"
+	}
+	if proj.PointScale == "" {
+		proj.PointScale = "0,1,2,3"
+	}
+	if proj.VelocityAveragedOver == 0 {
+		proj.VelocityAveragedOver = 3
+	}
+	if proj.TimeZone == nil {
+		proj.TimeZone = &pivotal.TimeZone{OlsonName: "Etc/UTC", Offset: "+00:00"}
+	}
+	if proj.CreatedAt == nil {
+		proj.CreatedAt = s.now()
+	}
+	proj.UpdatedAt = proj.CreatedAt
+	proj.Kind = "project"
+
+	s.projects[proj.Id] = &project{
+		project:  proj,
+		tasks:    make(map[int][]*pivotal.Task),
+		comments: make(map[string][]*pivotal.Comment),
+	}
+	s.syncProjectIds()
+	return proj
+}
+
+func (s *Server) syncProjectIds() {
+	ids := []int{}
+	for _, p := range s.listProjects() {
+		ids = append(ids, p.Id)
+	}
+	s.me.ProjectIds = &ids
+}
+
+// Stories ---------------------------------------------------------------------
+
+func (p *project) story(id int) (int, *pivotal.Story) {
+	for i, story := range p.stories {
+		if story.Id == id {
+			return i, story
+		}
+	}
+	return -1, nil
+}
+
+func (p *project) labelByName(name string) *pivotal.Label {
+	for _, label := range p.labels {
+		if strings.EqualFold(label.Name, name) {
+			return label
+		}
+	}
+	return nil
+}
+
+func (s *Server) listStories(r *request, p *project) (interface{}, *apiError) {
+	state := r.query.Get("with_state")
+	storyType := r.query.Get("with_story_type")
+	label := r.query.Get("with_label")
+
+	stories := []*pivotal.Story{}
+	for _, story := range p.stories {
+		if state != "" && story.State != state {
+			continue
+		}
+		if storyType != "" && story.Type != storyType {
+			continue
+		}
+		if label != "" && !hasLabel(story, label) {
+			continue
+		}
+		stories = append(stories, story)
+	}
+
+	lo, hi, err := paginate(r, len(stories))
+	if err != nil {
+		return nil, err
+	}
+	stories = stories[lo:hi]
+	for i, story := range stories {
+		stories[i] = s.nested(r, p, story)
+	}
+	return stories, nil
+}
+
+func hasLabel(story *pivotal.Story, name string) bool {
+	if story.Labels == nil {
+		return false
+	}
+	for _, l := range *story.Labels {
+		if strings.EqualFold(l.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) addStory(p *project, story *pivotal.Story) *pivotal.Story {
+	if story.Id == 0 {
+		story.Id = s.newId()
+	}
+	story.ProjectId = p.project.Id
+	story.Tasks, story.Owners, story.Comments = nil, nil, nil
+	if story.Type == "" {
+		story.Type = pivotal.StoryTypeFeature
+	}
+	if story.State == "" {
+		story.State = pivotal.StoryStateUnscheduled
+	}
+	if story.RequestedById == 0 {
+		story.RequestedById = s.me.Id
+	}
+	if story.CreatedAt == nil {
+		story.CreatedAt = s.now()
+	}
+	story.UpdatedAt = story.CreatedAt
+	story.Kind = "story"
+	story.URL = fmt.Sprintf("https://www.pivotaltracker.com/story/show/%d", story.Id)
+	s.resolveLabels(p, story, story.Labels != nil)
+	p.stories = append(p.stories, story)
+	return story
+}
+
+func (s *Server) updateStory(r *request, p *project, i int, story *pivotal.Story) (interface{}, *apiError) {
+	var move struct {
+		BeforeId int `json:"before_id"`
+		AfterId  int `json:"after_id"`
+	}
+	if err := r.decode(&move); err != nil {
+		return nil, err
+	}
+	if err := r.decode(story); err != nil {
+		return nil, err
+	}
+	// Nested resources are read-only, see nested.
+	story.Tasks, story.Owners, story.Comments = nil, nil, nil
+	story.Id, story.ProjectId, story.Kind = p.stories[i].Id, p.project.Id, "story"
+	story.UpdatedAt = s.now()
+	if story.State == pivotal.StoryStateAccepted && story.AcceptedAt == nil {
+		story.AcceptedAt = story.UpdatedAt
+	}
+	s.resolveLabels(p, story, r.has("labels") && !r.has("label_ids"))
+
+	target := move.BeforeId
+	if target == 0 {
+		target = move.AfterId
+	}
+	if target != 0 && target != story.Id {
+		if _, other := p.story(target); other == nil {
+			return nil, errInvalidParameter("before_id", "must reference a story in the project")
+		}
+		p.stories = append(p.stories[:i:i], p.stories[i+1:]...)
+		j, _ := p.story(target)
+		if move.BeforeId == 0 {
+			j++
+		}
+		p.stories = append(p.stories[:j], append([]*pivotal.Story{story}, p.stories[j:]...)...)
+	}
+	return story, nil
+}
+
+// resolveLabels keeps Labels and LabelIds of a story consistent. When
+// byName is set, Labels is authoritative and unknown labels are created.
+func (s *Server) resolveLabels(p *project, story *pivotal.Story, byName bool) {
+	labels := []*pivotal.Label{}
+	ids := []int{}
+	if byName {
+		for _, l := range *story.Labels {
+			label := p.labelByName(l.Name)
+			if label == nil && l.Id != 0 {
+				for _, pl := range p.labels {
+					if pl.Id == l.Id {
+						label = pl
+					}
+				}
+			}
+			if label == nil {
+				label = s.addLabel(p, l.Name)
+			}
+			labels = append(labels, label)
+			ids = append(ids, label.Id)
+		}
+	} else if story.LabelIds != nil {
+		for _, id := range *story.LabelIds {
+			for _, label := range p.labels {
+				if label.Id == id {
+					labels = append(labels, label)
+					ids = append(ids, id)
+				}
+			}
+		}
+	}
+	story.Labels = &labels
+	story.LabelIds = &ids
+}
+
+// Labels and epics ------------------------------------------------------------
+
+func (s *Server) addLabel(p *project, name string) *pivotal.Label {
+	label := &pivotal.Label{
+		Id:        s.newId(),
+		ProjectId: p.project.Id,
+		Name:      name,
+		CreatedAt: s.now(),
+		Kind:      "label",
+	}
+	label.UpdatedAt = label.CreatedAt
+	p.labels = append(p.labels, label)
+	return label
+}
+
+func (s *Server) addEpic(p *project, epic *pivotal.Epic) *pivotal.Epic {
+	if epic.Id == 0 {
+		epic.Id = s.newId()
+	}
+	epic.ProjectId = p.project.Id
+	if epic.LabelId == 0 {
+		label := p.labelByName(epic.Name)
+		if label == nil {
+			label = s.addLabel(p, strings.ToLower(epic.Name))
+		}
+		epic.LabelId = label.Id
+	}
+	if epic.CreatedAt == nil {
+		epic.CreatedAt = s.now()
+	}
+	epic.UpdatedAt = epic.CreatedAt
+	epic.Kind = "epic"
+	p.epics = append(p.epics, epic)
+	return epic
+}
+
+func (s *Server) now() *time.Time {
+	t := s.Now()
+	return &t
+}
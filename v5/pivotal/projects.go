@@ -0,0 +1,164 @@
+// Copyright (C) 2015 Scott Devoid
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package pivotal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type Project struct {
+	Id                           int        `json:"id,omitempty"`
+	Name                         string     `json:"name,omitempty"`
+	Version                      int        `json:"version,omitempty"`
+	Description                  string     `json:"description,omitempty"`
+	ProfileContent               string     `json:"profile_content,omitempty"`
+	IterationLength              int        `json:"iteration_length,omitempty"`
+	WeekStartDay                 string     `json:"week_start_day,omitempty"`
+	PointScale                   string     `json:"point_scale,omitempty"`
+	PointScaleIsCustom           bool       `json:"point_scale_is_custom,omitempty"`
+	BugsAndChoresAreEstimatable  bool       `json:"bugs_and_chores_are_estimatable,omitempty"`
+	AutomaticPlanning            bool       `json:"automatic_planning,omitempty"`
+	EnableTasks                  bool       `json:"enable_tasks,omitempty"`
+	StartDate                    string     `json:"start_date,omitempty"`
+	TimeZone                     *TimeZone  `json:"time_zone,omitempty"`
+	VelocityAveragedOver         int        `json:"velocity_averaged_over,omitempty"`
+	NumberOfDoneIterationsToShow int        `json:"number_of_done_iterations_to_show,omitempty"`
+	InitialVelocity              int        `json:"initial_velocity,omitempty"`
+	CurrentIterationNumber       int        `json:"current_iteration_number,omitempty"`
+	EnableIncomingEmails         bool       `json:"enable_incoming_emails,omitempty"`
+	AtomEnabled                  bool       `json:"atom_enabled,omitempty"`
+	Public                       bool       `json:"public,omitempty"`
+	ProjectType                  string     `json:"project_type,omitempty"`
+	AccountId                    int        `json:"account_id,omitempty"`
+	CreatedAt                    *time.Time `json:"created_at,omitempty"`
+	UpdatedAt                    *time.Time `json:"updated_at,omitempty"`
+	Kind                         string     `json:"kind,omitempty"`
+}
+
+// ProjectRequest is the payload for creating or updating a project.
+// Boolean settings are pointers so that false can be sent explicitly.
+type ProjectRequest struct {
+	Name                         string    `json:"name,omitempty"`
+	NewAccountName               string    `json:"new_account_name,omitempty"`
+	AccountId                    int       `json:"account_id,omitempty"`
+	Description                  string    `json:"description,omitempty"`
+	ProfileContent               string    `json:"profile_content,omitempty"`
+	IterationLength              int       `json:"iteration_length,omitempty"`
+	WeekStartDay                 string    `json:"week_start_day,omitempty"`
+	PointScale                   string    `json:"point_scale,omitempty"`
+	BugsAndChoresAreEstimatable  *bool     `json:"bugs_and_chores_are_estimatable,omitempty"`
+	AutomaticPlanning            *bool     `json:"automatic_planning,omitempty"`
+	EnableTasks                  *bool     `json:"enable_tasks,omitempty"`
+	StartDate                    string    `json:"start_date,omitempty"`
+	TimeZone                     *TimeZone `json:"time_zone,omitempty"`
+	VelocityAveragedOver         int       `json:"velocity_averaged_over,omitempty"`
+	NumberOfDoneIterationsToShow int       `json:"number_of_done_iterations_to_show,omitempty"`
+	InitialVelocity              int       `json:"initial_velocity,omitempty"`
+	EnableIncomingEmails         *bool     `json:"enable_incoming_emails,omitempty"`
+	AtomEnabled                  *bool     `json:"atom_enabled,omitempty"`
+	Public                       *bool     `json:"public,omitempty"`
+}
+
+// ProjectsService provides endpoints beneath '/projects' in the Pivotal API.
+// Use Client.Project to reach the resources of a single project.
+type ProjectsService struct {
+	client *Client
+}
+
+func newProjectsService(client *Client) *ProjectsService {
+	return &ProjectsService{client}
+}
+
+func (s *ProjectsService) List(opts ...RequestOption) ([]*Project, *http.Response, error) {
+	return s.ListContext(context.Background(), opts...)
+}
+
+func (s *ProjectsService) ListContext(ctx context.Context, opts ...RequestOption) ([]*Project, *http.Response, error) {
+	req, err := s.client.NewRequestContext(ctx, "GET", "projects", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, opt := range opts {
+		opt(req)
+	}
+	var projects []*Project
+	resp, err := s.client.Do(req, &projects)
+	if err != nil {
+		return nil, resp, err
+	}
+	return projects, resp, err
+}
+
+func (s *ProjectsService) Get(id int) (*Project, *http.Response, error) {
+	return s.GetContext(context.Background(), id)
+}
+
+func (s *ProjectsService) GetContext(ctx context.Context, id int) (*Project, *http.Response, error) {
+	u := fmt.Sprintf("projects/%v", id)
+	req, err := s.client.NewRequestContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	var project Project
+	resp, err := s.client.Do(req, &project)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &project, resp, err
+}
+
+func (s *ProjectsService) Create(project ProjectRequest) (*Project, *http.Response, error) {
+	return s.CreateContext(context.Background(), project)
+}
+
+func (s *ProjectsService) CreateContext(ctx context.Context, project ProjectRequest) (*Project, *http.Response, error) {
+	if project.Name == "" {
+		return nil, nil, &ErrFieldNotSet{"name"}
+	}
+	req, err := s.client.NewRequestContext(ctx, "POST", "projects", project)
+	if err != nil {
+		return nil, nil, err
+	}
+	var p Project
+	resp, err := s.client.Do(req, &p)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &p, resp, err
+}
+
+func (s *ProjectsService) Update(id int, project ProjectRequest) (*Project, *http.Response, error) {
+	return s.UpdateContext(context.Background(), id, project)
+}
+
+func (s *ProjectsService) UpdateContext(ctx context.Context, id int, project ProjectRequest) (*Project, *http.Response, error) {
+	u := fmt.Sprintf("projects/%v", id)
+	req, err := s.client.NewRequestContext(ctx, "PUT", u, project)
+	if err != nil {
+		return nil, nil, err
+	}
+	var p Project
+	resp, err := s.client.Do(req, &p)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &p, resp, err
+}
+
+func (s *ProjectsService) Delete(id int) (*http.Response, error) {
+	return s.DeleteContext(context.Background(), id)
+}
+
+func (s *ProjectsService) DeleteContext(ctx context.Context, id int) (*http.Response, error) {
+	u := fmt.Sprintf("projects/%v", id)
+	req, err := s.client.NewRequestContext(ctx, "DELETE", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.client.Do(req, nil)
+}
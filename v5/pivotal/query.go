@@ -0,0 +1,185 @@
+// Copyright (C) 2015 Scott Devoid
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package pivotal
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// searchDateFormat is the date format understood by Tracker search.
+const searchDateFormat = "01/02/2006"
+
+// Query is a Tracker search expression. Build terms with the Query*
+// functions and combine them with And, Or and Not:
+//
+//	q := And(QueryLabel("needs review"), Or(QueryType(StoryTypeBug), QueryType(StoryTypeChore)))
+//	stories, _, err := client.Project(id).Stories.List(FilterQuery(q))
+type Query struct {
+	expr string
+	// op is the operator joining the top level of expr, if any.
+	op string
+}
+
+// String renders the query in Tracker search syntax.
+func (q Query) String() string {
+	return q.expr
+}
+
+// IsZero reports whether q is the empty query.
+func (q Query) IsZero() bool {
+	return q.expr == ""
+}
+
+// And combines q with the given queries, all of which must match.
+func (q Query) And(others ...Query) Query {
+	return And(append([]Query{q}, others...)...)
+}
+
+// Or combines q with the given queries, any of which must match.
+func (q Query) Or(others ...Query) Query {
+	return Or(append([]Query{q}, others...)...)
+}
+
+// And matches stories matching all of qs.
+func And(qs ...Query) Query {
+	return join("AND", qs)
+}
+
+// Or matches stories matching any of qs.
+func Or(qs ...Query) Query {
+	return join("OR", qs)
+}
+
+// Not matches stories not matching q.
+func Not(q Query) Query {
+	switch {
+	case q.IsZero():
+		return q
+	case q.op != "":
+		return Query{expr: "-(" + q.expr + ")"}
+	case strings.HasPrefix(q.expr, "-"):
+		return Query{expr: strings.TrimPrefix(q.expr, "-")}
+	}
+	return Query{expr: "-" + q.expr}
+}
+
+func join(op string, qs []Query) Query {
+	parts := make([]string, 0, len(qs))
+	for _, q := range qs {
+		switch {
+		case q.IsZero():
+			continue
+		case q.op != "" && q.op != op:
+			parts = append(parts, "("+q.expr+")")
+		default:
+			parts = append(parts, q.expr)
+		}
+	}
+	switch len(parts) {
+	case 0:
+		return Query{}
+	case 1:
+		return Query{expr: parts[0]}
+	}
+	return Query{expr: strings.Join(parts, " "+op+" "), op: op}
+}
+
+// QueryTerm matches field:value, quoting value as needed.
+func QueryTerm(field, value string) Query {
+	return Query{expr: field + ":" + quoteSearch(value)}
+}
+
+// QueryText matches free text in story names, descriptions and comments.
+func QueryText(text string) Query {
+	return Query{expr: quoteSearch(text)}
+}
+
+// QueryState matches stories in any of the given StoryState* states.
+func QueryState(states ...string) Query {
+	return terms("state", states)
+}
+
+// QueryType matches stories of any of the given StoryType* types.
+func QueryType(types ...string) Query {
+	return terms("type", types)
+}
+
+// QueryLabel matches stories carrying any of the given labels.
+func QueryLabel(labels ...string) Query {
+	return terms("label", labels)
+}
+
+// QueryOwner matches stories owned by any of the given people, named by
+// initials, username or name.
+func QueryOwner(people ...string) Query {
+	return terms("owner", people)
+}
+
+// QueryRequester matches stories requested by any of the given people.
+func QueryRequester(people ...string) Query {
+	return terms("requester", people)
+}
+
+// QueryEstimate matches stories estimated at the given points.
+func QueryEstimate(points float64) Query {
+	return QueryTerm("estimate", strconv.FormatFloat(points, 'f', -1, 64))
+}
+
+// QueryCreated matches stories created in [from, to). A zero time leaves
+// that end of the range open.
+func QueryCreated(from, to time.Time) Query {
+	return dateRange("created", from, to)
+}
+
+// QueryUpdated matches stories updated in [from, to).
+func QueryUpdated(from, to time.Time) Query {
+	return dateRange("updated", from, to)
+}
+
+// QueryAccepted matches stories accepted in [from, to).
+func QueryAccepted(from, to time.Time) Query {
+	return dateRange("accepted", from, to)
+}
+
+// QueryIncludeDone controls whether accepted stories from past iterations
+// are searched, which Tracker does not do by default.
+func QueryIncludeDone(include bool) Query {
+	return QueryTerm("includedone", strconv.FormatBool(include))
+}
+
+// FilterQuery filters a story listing with q, see Filter.
+func FilterQuery(q Query) RequestOption {
+	return Filter(q.String())
+}
+
+func terms(field string, values []string) Query {
+	qs := make([]Query, len(values))
+	for i, v := range values {
+		qs[i] = QueryTerm(field, v)
+	}
+	return Or(qs...)
+}
+
+func dateRange(field string, from, to time.Time) Query {
+	var since, before Query
+	if !from.IsZero() {
+		since = QueryTerm(field+"_since", from.Format(searchDateFormat))
+	}
+	if !to.IsZero() {
+		before = QueryTerm(field+"_before", to.Format(searchDateFormat))
+	}
+	return And(since, before)
+}
+
+// quoteSearch quotes s when it contains characters that have a meaning
+// in the search syntax.
+func quoteSearch(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\n:\"()-,") {
+		return s
+	}
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
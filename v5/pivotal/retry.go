@@ -0,0 +1,184 @@
+// Copyright (C) 2015 Scott Devoid
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package pivotal
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how Client.Do re-sends requests that failed with
+// a transport error or a transient status (429 and 5xx gateway errors).
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Values below 2 disable retrying.
+	MaxAttempts int
+
+	// MinBackoff is the base delay; it doubles on every attempt, is
+	// capped at MaxBackoff and jittered to avoid synchronized retries.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// RetryNonIdempotent allows POST and PATCH requests to be retried.
+	// By default only idempotent methods are re-sent.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy is a reasonable policy for long-running jobs.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	MinBackoff:  500 * time.Millisecond,
+	MaxBackoff:  30 * time.Second,
+}
+
+// SetRetryPolicy enables retrying in Do. Passing nil disables it again.
+func (c *Client) SetRetryPolicy(policy *RetryPolicy) {
+	c.retry = policy
+}
+
+// ErrRetriesExhausted is returned by Do when every attempt of a request
+// failed with a transport error.
+type ErrRetriesExhausted struct {
+	Attempts int
+	Err      error
+}
+
+func (err *ErrRetriesExhausted) Error() string {
+	return fmt.Sprintf("giving up after %d attempts: %v", err.Attempts, err.Err)
+}
+
+func (err *ErrRetriesExhausted) Unwrap() error {
+	return err.Err
+}
+
+// send performs req according to the client's retry policy and returns
+// the last response along with the number of attempts it took.
+func (c *Client) send(req *http.Request) (*http.Response, int, error) {
+	policy := c.retry
+	for attempt := 1; ; attempt++ {
+		// Middleware works on a fresh copy for every attempt, so that it
+		// never sees the changes it made to an earlier one.
+		r, err := rewindRequest(req)
+		if err != nil {
+			return nil, attempt - 1, err
+		}
+		if err := c.processRequest(r); err != nil {
+			return nil, attempt - 1, err
+		}
+		resp, err := c.client.Do(r)
+		if policy == nil || attempt >= policy.MaxAttempts || !policy.shouldRetry(req, resp, err) {
+			if err != nil && attempt > 1 {
+				err = &ErrRetriesExhausted{Attempts: attempt, Err: err}
+			}
+			return resp, attempt, err
+		}
+
+		wait := policy.backoff(attempt, resp)
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, attempt, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// rewindRequest returns a copy of req with a fresh body, so the buffered
+// payload built by NewRequest can be sent again. A body without GetBody is
+// shared with the copy; such requests are never retried.
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	r := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
+	return r, nil
+}
+
+func (p *RetryPolicy) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	// A body that cannot be rewound would be sent empty or truncated.
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	if !p.RetryNonIdempotent {
+		switch req.Method {
+		case "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+		default:
+			return false
+		}
+	}
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// backoff returns how long to wait before the next attempt. A Retry-After
+// header sent by Tracker always takes precedence over the computed delay.
+func (p *RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if d, ok := retryAfter(resp); ok {
+			return d
+		}
+	}
+
+	d := p.MinBackoff
+	for i := 1; i < attempt && (p.MaxBackoff <= 0 || d < p.MaxBackoff); i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	// Equal jitter: wait at least half of d, plus a random share of the rest.
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(d-half)+1))
+}
+
+// retryAfter parses the Retry-After header, which may hold either a
+// number of seconds or an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
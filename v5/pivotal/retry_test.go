@@ -0,0 +1,98 @@
+// Copyright (C) 2015 Scott Devoid
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package pivotal_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/salsita/go-pivotaltracker/v5/pivotal"
+)
+
+// flakyServer fails the first failures requests with 503 and the given
+// Retry-After header, then answers with an empty story.
+func flakyServer(t *testing.T, failures int32, retryAfter string) (*httptest.Server, *int32) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if atomic.AddInt32(&calls, 1) <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(http.StatusServiceUnavailable)
+			io.WriteString(w, `{"kind":"error","code":"unavailable","error":"try again"}`)
+			return
+		}
+		io.WriteString(w, `{"id":1,"name":"story","kind":"story"}`)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	srv, calls := flakyServer(t, 2, "1")
+	client := pivotal.NewClient("token", pivotal.WithRetryPolicy(&pivotal.RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  time.Hour,
+		MaxBackoff:  time.Hour,
+	}))
+	client.SetBaseURL(srv.URL + "/services/v5/")
+
+	start := time.Now()
+	story, _, err := client.Project(1).Stories.Get(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if story.Name != "story" {
+		t.Errorf("name = %q, want %q", story.Name, "story")
+	}
+	if n := atomic.LoadInt32(calls); n != 3 {
+		t.Errorf("attempts = %d, want 3", n)
+	}
+	// The one second Retry-After must win over the hour long backoff.
+	if d := time.Since(start); d < 2*time.Second || d > 10*time.Second {
+		t.Errorf("took %v, want about 2s", d)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	srv, calls := flakyServer(t, 5, "0")
+	client := pivotal.NewClient("token", pivotal.WithRetryPolicy(&pivotal.RetryPolicy{MaxAttempts: 2}))
+	client.SetBaseURL(srv.URL + "/services/v5/")
+
+	_, _, err := client.Project(1).Stories.Get(1)
+	var apiErr *pivotal.ErrAPI
+	if !errors.As(err, &apiErr) || apiErr.Attempts != 2 {
+		t.Fatalf("err = %v, want *ErrAPI after 2 attempts", err)
+	}
+	if n := atomic.LoadInt32(calls); n != 2 {
+		t.Errorf("attempts = %d, want 2", n)
+	}
+}
+
+func TestRetrySkipsBodyThatCannotBeRewound(t *testing.T) {
+	srv, calls := flakyServer(t, 1, "0")
+	client := pivotal.NewClient("token", pivotal.WithRetryPolicy(&pivotal.RetryPolicy{MaxAttempts: 3}))
+
+	body := io.NopCloser(strings.NewReader(`{"name":"story"}`))
+	req, err := http.NewRequest("PUT", srv.URL+"/services/v5/projects/1/stories/1", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Do(req, nil)
+	var apiErr *pivotal.ErrAPI
+	if !errors.As(err, &apiErr) || apiErr.Response.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("err = %v, want the 503", err)
+	}
+	if n := atomic.LoadInt32(calls); n != 1 {
+		t.Errorf("attempts = %d, want 1", n)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright (C) 2015 Scott Devoid
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package pivotal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// SearchResult is the result of a project search.
+type SearchResult struct {
+	Query   string             `json:"query,omitempty"`
+	Stories *StorySearchResult `json:"stories,omitempty"`
+	Epics   *EpicSearchResult  `json:"epics,omitempty"`
+	Kind    string             `json:"kind,omitempty"`
+}
+
+type StorySearchResult struct {
+	Stories              []*Story `json:"stories,omitempty"`
+	TotalHits            int      `json:"total_hits,omitempty"`
+	TotalHitsWithDone    int      `json:"total_hits_with_done,omitempty"`
+	TotalPoints          float64  `json:"total_points,omitempty"`
+	TotalPointsCompleted float64  `json:"total_points_completed,omitempty"`
+	Kind                 string   `json:"kind,omitempty"`
+}
+
+// PointsRemaining returns the points of the matched stories that are
+// not completed yet.
+func (r *StorySearchResult) PointsRemaining() float64 {
+	return r.TotalPoints - r.TotalPointsCompleted
+}
+
+type EpicSearchResult struct {
+	Epics     []*Epic `json:"epics,omitempty"`
+	TotalHits int     `json:"total_hits,omitempty"`
+	Kind      string  `json:"kind,omitempty"`
+}
+
+// SearchService provides the '/projects/:id/search' endpoint.
+type SearchService struct {
+	client    *Client
+	projectId string
+}
+
+func newSearchService(client *Client, projectId string) *SearchService {
+	return &SearchService{client, projectId}
+}
+
+// Find runs a Tracker search, e.g. Query.String() of a built query, and
+// returns the matching stories and epics along with point totals.
+func (s *SearchService) Find(query string, opts ...RequestOption) (*SearchResult, *http.Response, error) {
+	return s.FindContext(context.Background(), query, opts...)
+}
+
+func (s *SearchService) FindContext(ctx context.Context, query string, opts ...RequestOption) (*SearchResult, *http.Response, error) {
+	if query == "" {
+		return nil, nil, &ErrFieldNotSet{"query"}
+	}
+	u := fmt.Sprintf("projects/%v/search", s.projectId)
+	req, err := s.client.NewRequestContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	urlAddParam(req, "query", query)
+	for _, opt := range opts {
+		opt(req)
+	}
+	var result SearchResult
+	resp, err := s.client.Do(req, &result)
+	if err != nil {
+		return nil, resp, err
+	}
+	if result.Stories == nil {
+		result.Stories = &StorySearchResult{}
+	}
+	if result.Epics == nil {
+		result.Epics = &EpicSearchResult{}
+	}
+	return &result, resp, err
+}
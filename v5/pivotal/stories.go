@@ -5,10 +5,9 @@
 package pivotal
 
 import (
+	"context"
 	"fmt"
-	"io"
 	"net/http"
-	"sync"
 	"time"
 )
 
@@ -45,9 +44,16 @@ type Story struct {
 	LabelIds      *[]int     `json:"label_ids,omitempty"`
 	Labels        *[]*Label  `json:"labels,omitempty"`
 	TaskIds       *[]int     `json:"task_ids,omitempty"`
-	Tasks         *[]int     `json:"tasks,omitempty"`
 	FollowerIds   *[]int     `json:"follower_ids,omitempty"`
 	CommentIds    *[]int     `json:"comment_ids,omitempty"`
+
+	// Nested resources, only returned when requested with IncludeTasks,
+	// IncludeOwners and IncludeComments respectively. They are read-only
+	// and left out when the story is created or updated.
+	Tasks    *[]*Task    `json:"tasks,omitempty"`
+	Owners   *[]*Person  `json:"owners,omitempty"`
+	Comments *[]*Comment `json:"comments,omitempty"`
+
 	CreatedAt     *time.Time `json:"created_at,omitempty"`
 	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
 	IntegrationId int        `json:"integration_id,omitempty"`
@@ -56,6 +62,69 @@ type Story struct {
 	Kind          string     `json:"kind,omitempty"`
 }
 
+// writable returns a copy of the story without the nested resources,
+// which are read-only and must not be sent back to Tracker.
+func (s *Story) writable() *Story {
+	w := *s
+	w.Tasks, w.Owners, w.Comments = nil, nil, nil
+	return &w
+}
+
+// StoryUpdate is a partial story update. Unset fields are left alone,
+// fields set to Null are cleared, e.g. to remove the estimate and all
+// owners:
+//
+//	stories.Edit(id, StoryUpdate{
+//		Estimate: Null[float64](),
+//		OwnerIds: Null[[]int](),
+//	})
+type StoryUpdate struct {
+	Name          Nullable[string]    `json:"name,omitzero"`
+	Description   Nullable[string]    `json:"description,omitzero"`
+	Type          Nullable[string]    `json:"story_type,omitzero"`
+	State         Nullable[string]    `json:"current_state,omitzero"`
+	Estimate      Nullable[float64]   `json:"estimate,omitzero"`
+	AcceptedAt    Nullable[time.Time] `json:"accepted_at,omitzero"`
+	Deadline      Nullable[time.Time] `json:"deadline,omitzero"`
+	RequestedById Nullable[int]       `json:"requested_by_id,omitzero"`
+	OwnerIds      Nullable[[]int]     `json:"owner_ids,omitzero"`
+	LabelIds      Nullable[[]int]     `json:"label_ids,omitzero"`
+	Labels        Nullable[[]*Label]  `json:"labels,omitzero"`
+	FollowerIds   Nullable[[]int]     `json:"follower_ids,omitzero"`
+	IntegrationId Nullable[int]       `json:"integration_id,omitzero"`
+	ExternalId    Nullable[string]    `json:"external_id,omitzero"`
+}
+
+func (u StoryUpdate) MarshalJSON() ([]byte, error) {
+	return marshalUpdate(u)
+}
+
+// story returns the values set by the update, for validation.
+func (u *StoryUpdate) story() *Story {
+	s := &Story{
+		Estimate:   u.Estimate.ptr(),
+		AcceptedAt: u.AcceptedAt.ptr(),
+		Deadline:   u.Deadline.ptr(),
+		OwnerIds:   u.OwnerIds.ptr(),
+		LabelIds:   u.LabelIds.ptr(),
+		Labels:     u.Labels.ptr(),
+	}
+	s.Name, _ = u.Name.Get()
+	s.Description, _ = u.Description.Get()
+	s.Type, _ = u.Type.Get()
+	s.State, _ = u.State.Get()
+	return s
+}
+
+// IsEmpty reports whether the update leaves every field alone.
+func (u *StoryUpdate) IsEmpty() bool {
+	return u.Name.IsZero() && u.Description.IsZero() && u.Type.IsZero() &&
+		u.State.IsZero() && u.Estimate.IsZero() && u.AcceptedAt.IsZero() &&
+		u.Deadline.IsZero() && u.RequestedById.IsZero() && u.OwnerIds.IsZero() &&
+		u.LabelIds.IsZero() && u.Labels.IsZero() && u.FollowerIds.IsZero() &&
+		u.IntegrationId.IsZero() && u.ExternalId.IsZero()
+}
+
 type Task struct {
 	Id          int        `json:"id,omitempty"`
 	StoryId     int        `json:"story_id,omitempty"`
@@ -66,6 +135,14 @@ type Task struct {
 	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
 }
 
+// TaskRequest is the payload for updating a task. Complete is a pointer
+// so that a task can be marked incomplete again.
+type TaskRequest struct {
+	Description string `json:"description,omitempty"`
+	Complete    *bool  `json:"complete,omitempty"`
+	Position    int    `json:"position,omitempty"`
+}
+
 type Person struct {
 	Id       int    `json:"id,omitempty"`
 	Name     string `json:"name,omitempty"`
@@ -87,6 +164,37 @@ type Comment struct {
 	CommitIdentifier    string     `json:"commit_identifier,omitempty"`
 	CreatedAt           *time.Time `json:"created_at,omitempty"`
 	UpdatedAt           *time.Time `json:"updated_at,omitempty"`
+	Kind                string     `json:"kind,omitempty"`
+}
+
+// StoryMoveRequest repositions a story. BeforeId and AfterId place it
+// relative to another story; State moves it between panels, use
+// StoryStateUnscheduled for the icebox and StoryStateUnstarted for the
+// backlog.
+type StoryMoveRequest struct {
+	BeforeId int    `json:"before_id,omitempty"`
+	AfterId  int    `json:"after_id,omitempty"`
+	State    string `json:"current_state,omitempty"`
+}
+
+// MoveBefore places a story directly before the given story.
+func MoveBefore(storyId int) StoryMoveRequest {
+	return StoryMoveRequest{BeforeId: storyId}
+}
+
+// MoveAfter places a story directly after the given story.
+func MoveAfter(storyId int) StoryMoveRequest {
+	return StoryMoveRequest{AfterId: storyId}
+}
+
+// MoveToIcebox moves a story into the icebox.
+func MoveToIcebox() StoryMoveRequest {
+	return StoryMoveRequest{State: StoryStateUnscheduled}
+}
+
+// MoveToBacklog moves a story into the backlog.
+func MoveToBacklog() StoryMoveRequest {
+	return StoryMoveRequest{State: StoryStateUnstarted}
 }
 
 type StoryService struct {
@@ -98,9 +206,9 @@ func newStoryService(client *Client, projectId string) *StoryService {
 	return &StoryService{client, projectId}
 }
 
-func (s *StoryService) setupReq(opts []RequestOption) (req *http.Request, err error) {
+func (s *StoryService) setupReq(ctx context.Context, opts []RequestOption) (req *http.Request, err error) {
 	u := fmt.Sprintf("projects/%v/stories", s.projectId)
-	req, err = s.client.NewRequest("GET", u, nil)
+	req, err = s.client.NewRequestContext(ctx, "GET", u, nil)
 	if err != nil {
 		return
 	}
@@ -111,7 +219,11 @@ func (s *StoryService) setupReq(opts []RequestOption) (req *http.Request, err er
 }
 
 func (s *StoryService) List(opts ...RequestOption) ([]*Story, *http.Response, error) {
-	req, err := s.setupReq(opts)
+	return s.ListContext(context.Background(), opts...)
+}
+
+func (s *StoryService) ListContext(ctx context.Context, opts ...RequestOption) ([]*Story, *http.Response, error) {
+	req, err := s.setupReq(ctx, opts)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -125,49 +237,48 @@ func (s *StoryService) List(opts ...RequestOption) ([]*Story, *http.Response, er
 	return stories, resp, err
 }
 
-type StoryCursor struct {
-	*cursor
-	buff []*Story
-	lock *sync.Mutex
+type StoryCursor = Cursor[Story]
+
+func (s *StoryService) Iterate(opts ...RequestOption) (c *StoryCursor, err error) {
+	return s.IterateContext(context.Background(), opts...)
 }
 
-func (c *StoryCursor) Next() (s *Story, err error) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-	if len(c.buff) == 0 {
-		_, err = c.next(&c.buff)
-		if err != nil {
-			return nil, err
-		}
+// IterateContext returns a cursor over the project's stories. Every page
+// the cursor fetches is bound to ctx.
+func (s *StoryService) IterateContext(ctx context.Context, opts ...RequestOption) (c *StoryCursor, err error) {
+	req_fn := func(ctx context.Context) (req *http.Request) {
+		req, _ = s.setupReq(ctx, opts)
+		return req
 	}
+	return newTypedCursor[Story](ctx, s.client, req_fn)
+}
 
-	if len(c.buff) == 0 {
-		err = io.EOF
-	} else {
-		s, c.buff = c.buff[0], c.buff[1:]
-	}
-	return s, err
+// IterateFrom resumes iterating at a position saved with Cursor.Position.
+func (s *StoryService) IterateFrom(pos CursorPosition) (*StoryCursor, error) {
+	return s.IterateFromContext(context.Background(), pos)
 }
 
-func (s *StoryService) Iterate(opts ...RequestOption) (c *StoryCursor, err error) {
-	req_fn := func() (req *http.Request) {
-		req, _ = s.setupReq(opts)
+func (s *StoryService) IterateFromContext(ctx context.Context, pos CursorPosition) (*StoryCursor, error) {
+	req_fn := func(ctx context.Context) (req *http.Request) {
+		req, _ = s.setupReq(ctx, nil)
 		return req
 	}
-	cc, err := newCursor(s.client, req_fn)
-	return &StoryCursor{
-		cursor: cc,
-		buff:   make([]*Story, 0),
-		lock:   &sync.Mutex{},
-	}, err
+	return resumeTypedCursor[Story](ctx, s.client, req_fn, pos)
+}
+
+func (service *StoryService) Get(storyId int, opts ...RequestOption) (*Story, *http.Response, error) {
+	return service.GetContext(context.Background(), storyId, opts...)
 }
 
-func (service *StoryService) Get(storyId int) (*Story, *http.Response, error) {
+func (service *StoryService) GetContext(ctx context.Context, storyId int, opts ...RequestOption) (*Story, *http.Response, error) {
 	u := fmt.Sprintf("projects/%v/stories/%v", service.projectId, storyId)
-	req, err := service.client.NewRequest("GET", u, nil)
+	req, err := service.client.NewRequestContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, nil, err
 	}
+	for _, opt := range opts {
+		opt(req)
+	}
 
 	var story Story
 	resp, err := service.client.Do(req, &story)
@@ -179,8 +290,16 @@ func (service *StoryService) Get(storyId int) (*Story, *http.Response, error) {
 }
 
 func (service *StoryService) Update(storyId int, story *Story) (*Story, *http.Response, error) {
+	return service.UpdateContext(context.Background(), storyId, story)
+}
+
+func (service *StoryService) UpdateContext(ctx context.Context, storyId int, story *Story) (*Story, *http.Response, error) {
+	if err := service.validateStory(ctx, story); err != nil {
+		return nil, nil, err
+	}
+
 	u := fmt.Sprintf("projects/%v/stories/%v", service.projectId, storyId)
-	req, err := service.client.NewRequest("PUT", u, story)
+	req, err := service.client.NewRequestContext(ctx, "PUT", u, story.writable())
 	if err != nil {
 		return nil, nil, err
 	}
@@ -195,9 +314,103 @@ func (service *StoryService) Update(storyId int, story *Story) (*Story, *http.Re
 
 }
 
+// Edit applies a partial update, which unlike Update can clear fields.
+func (service *StoryService) Edit(storyId int, update StoryUpdate) (*Story, *http.Response, error) {
+	return service.EditContext(context.Background(), storyId, update)
+}
+
+func (service *StoryService) EditContext(ctx context.Context, storyId int, update StoryUpdate) (*Story, *http.Response, error) {
+	if err := service.validateStory(ctx, update.story()); err != nil {
+		return nil, nil, err
+	}
+
+	u := fmt.Sprintf("projects/%v/stories/%v", service.projectId, storyId)
+	req, err := service.client.NewRequestContext(ctx, "PUT", u, update)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var story Story
+	resp, err := service.client.Do(req, &story)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &story, resp, err
+}
+
+func (service *StoryService) Create(story *Story) (*Story, *http.Response, error) {
+	return service.CreateContext(context.Background(), story)
+}
+
+func (service *StoryService) CreateContext(ctx context.Context, story *Story) (*Story, *http.Response, error) {
+	if story.Name == "" {
+		return nil, nil, &ErrFieldNotSet{"name"}
+	}
+	if err := service.validateStory(ctx, story); err != nil {
+		return nil, nil, err
+	}
+
+	u := fmt.Sprintf("projects/%v/stories", service.projectId)
+	req, err := service.client.NewRequestContext(ctx, "POST", u, story.writable())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var newStory Story
+	resp, err := service.client.Do(req, &newStory)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &newStory, resp, err
+}
+
+func (service *StoryService) Delete(storyId int) (*http.Response, error) {
+	return service.DeleteContext(context.Background(), storyId)
+}
+
+func (service *StoryService) DeleteContext(ctx context.Context, storyId int) (*http.Response, error) {
+	u := fmt.Sprintf("projects/%v/stories/%v", service.projectId, storyId)
+	req, err := service.client.NewRequestContext(ctx, "DELETE", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.client.Do(req, nil)
+}
+
+func (service *StoryService) Move(storyId int, move StoryMoveRequest) (*Story, *http.Response, error) {
+	return service.MoveContext(context.Background(), storyId, move)
+}
+
+func (service *StoryService) MoveContext(ctx context.Context, storyId int, move StoryMoveRequest) (*Story, *http.Response, error) {
+	if move.BeforeId == 0 && move.AfterId == 0 && move.State == "" {
+		return nil, nil, &ErrFieldNotSet{"before_id"}
+	}
+
+	u := fmt.Sprintf("projects/%v/stories/%v", service.projectId, storyId)
+	req, err := service.client.NewRequestContext(ctx, "PUT", u, move)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var story Story
+	resp, err := service.client.Do(req, &story)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &story, resp, err
+}
+
 func (service *StoryService) ListTasks(storyId int) ([]*Task, *http.Response, error) {
+	return service.ListTasksContext(context.Background(), storyId)
+}
+
+func (service *StoryService) ListTasksContext(ctx context.Context, storyId int) ([]*Task, *http.Response, error) {
 	u := fmt.Sprintf("projects/%v/stories/%v/tasks", service.projectId, storyId)
-	req, err := service.client.NewRequest("GET", u, nil)
+	req, err := service.client.NewRequestContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -211,48 +424,142 @@ func (service *StoryService) ListTasks(storyId int) ([]*Task, *http.Response, er
 	return tasks, resp, err
 }
 
+// AddTask creates a task, discarding the server's copy of it.
+// Use CreateTask to get the created task back.
 func (service *StoryService) AddTask(storyId int, task *Task) (*http.Response, error) {
+	return service.AddTaskContext(context.Background(), storyId, task)
+}
+
+func (service *StoryService) AddTaskContext(ctx context.Context, storyId int, task *Task) (*http.Response, error) {
+	_, resp, err := service.CreateTaskContext(ctx, storyId, task)
+	return resp, err
+}
+
+func (service *StoryService) CreateTask(storyId int, task *Task) (*Task, *http.Response, error) {
+	return service.CreateTaskContext(context.Background(), storyId, task)
+}
+
+func (service *StoryService) CreateTaskContext(ctx context.Context, storyId int, task *Task) (*Task, *http.Response, error) {
 	if task.Description == "" {
-		return nil, &ErrFieldNotSet{"description"}
+		return nil, nil, &ErrFieldNotSet{"description"}
+	}
+	if service.client.validate {
+		if err := task.Validate(); err != nil {
+			return nil, nil, err
+		}
 	}
 
 	u := fmt.Sprintf("projects/%v/stories/%v/tasks", service.projectId, storyId)
-	req, err := service.client.NewRequest("POST", u, task)
+	req, err := service.client.NewRequestContext(ctx, "POST", u, task)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return service.client.Do(req, nil)
+	var newTask Task
+	resp, err := service.client.Do(req, &newTask)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &newTask, resp, err
 }
 
-func (service *StoryService) ListOwners(storyId int) ([]*Person, *http.Response, error) {
-	u := fmt.Sprintf("projects/%d/stories/%d/owners", service.projectId, storyId)
-	req, err := service.client.NewRequest("GET", u, nil)
+func (service *StoryService) GetTask(storyId, taskId int) (*Task, *http.Response, error) {
+	return service.GetTaskContext(context.Background(), storyId, taskId)
+}
+
+func (service *StoryService) GetTaskContext(ctx context.Context, storyId, taskId int) (*Task, *http.Response, error) {
+	u := fmt.Sprintf("projects/%v/stories/%v/tasks/%v", service.projectId, storyId, taskId)
+	req, err := service.client.NewRequestContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	var owners []*Person
-	resp, err := service.client.Do(req, &owners)
+	var task Task
+	resp, err := service.client.Do(req, &task)
 	if err != nil {
 		return nil, resp, err
 	}
 
-	return owners, resp, err
+	return &task, resp, err
 }
 
-func (service *StoryService) AddComment(storyId int, comment *Comment) (*Comment, *http.Response, error) {
-	u := fmt.Sprintf("projects/%v/stories/%v/comments", service.projectId, storyId)
-	req, err := service.client.NewRequest("POST", u, comment)
+func (service *StoryService) UpdateTask(storyId, taskId int, task TaskRequest) (*Task, *http.Response, error) {
+	return service.UpdateTaskContext(context.Background(), storyId, taskId, task)
+}
+
+func (service *StoryService) UpdateTaskContext(ctx context.Context, storyId, taskId int, task TaskRequest) (*Task, *http.Response, error) {
+	u := fmt.Sprintf("projects/%v/stories/%v/tasks/%v", service.projectId, storyId, taskId)
+	req, err := service.client.NewRequestContext(ctx, "PUT", u, task)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var bodyTask Task
+	resp, err := service.client.Do(req, &bodyTask)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &bodyTask, resp, err
+}
+
+// ReorderTask moves a task to the given 1-based position within its story.
+func (service *StoryService) ReorderTask(storyId, taskId, position int) (*Task, *http.Response, error) {
+	return service.ReorderTaskContext(context.Background(), storyId, taskId, position)
+}
+
+func (service *StoryService) ReorderTaskContext(ctx context.Context, storyId, taskId, position int) (*Task, *http.Response, error) {
+	if position < 1 {
+		return nil, nil, &ErrFieldNotSet{"position"}
+	}
+	return service.UpdateTaskContext(ctx, storyId, taskId, TaskRequest{Position: position})
+}
+
+func (service *StoryService) DeleteTask(storyId, taskId int) (*http.Response, error) {
+	return service.DeleteTaskContext(context.Background(), storyId, taskId)
+}
+
+func (service *StoryService) DeleteTaskContext(ctx context.Context, storyId, taskId int) (*http.Response, error) {
+	u := fmt.Sprintf("projects/%v/stories/%v/tasks/%v", service.projectId, storyId, taskId)
+	req, err := service.client.NewRequestContext(ctx, "DELETE", u, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.client.Do(req, nil)
+}
+
+func (service *StoryService) ListOwners(storyId int) ([]*Person, *http.Response, error) {
+	return service.ListOwnersContext(context.Background(), storyId)
+}
+
+func (service *StoryService) ListOwnersContext(ctx context.Context, storyId int) ([]*Person, *http.Response, error) {
+	u := fmt.Sprintf("projects/%v/stories/%v/owners", service.projectId, storyId)
+	req, err := service.client.NewRequestContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	var newComment Comment
-	resp, err := service.client.Do(req, &newComment)
+	var owners []*Person
+	resp, err := service.client.Do(req, &owners)
 	if err != nil {
 		return nil, resp, err
 	}
 
-	return &newComment, resp, err
+	return owners, resp, err
+}
+
+// Comments returns the comment service of the given story.
+func (service *StoryService) Comments(storyId int) *CommentService {
+	u := fmt.Sprintf("projects/%v/stories/%v", service.projectId, storyId)
+	return newCommentService(service.client, u)
+}
+
+func (service *StoryService) AddComment(storyId int, comment *Comment) (*Comment, *http.Response, error) {
+	return service.AddCommentContext(context.Background(), storyId, comment)
+}
+
+func (service *StoryService) AddCommentContext(ctx context.Context, storyId int, comment *Comment) (*Comment, *http.Response, error) {
+	return service.Comments(storyId).CreateContext(ctx, comment)
 }
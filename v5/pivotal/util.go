@@ -5,33 +5,53 @@
 package pivotal
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"io"
+	"iter"
 	"net/http"
+	"net/url"
 	"strconv"
 	"sync"
 )
 
-// requestFn is a function that returns a new *http.Request object.
-type requestFn func() (req *http.Request)
+var errInvalidRequest = errors.New("pivotal: failed to build request")
+
+// MaxPageSize is the largest number of items Tracker returns per request.
+const MaxPageSize = 500
+
+// requestFn is a function that returns a new *http.Request object
+// bound to the given context.
+type requestFn func(ctx context.Context) (req *http.Request)
 
 // cursor tracks response headers from paginated API responses.
 // And sets the appropriate URI variables in the next request.
 type cursor struct {
+	ctx       context.Context
 	client    *Client
 	requestFn requestFn
 	limit     int
 	offset    int
+	start     int
 	reqCount  int
 	lock      *sync.Mutex
+
+	// total is the item count reported by the last response. It stays
+	// -1 for endpoints that do not paginate and so return everything at once.
+	total int
 }
 
-func newCursor(client *Client, fn requestFn) (c *cursor, err error) {
+func newCursor(ctx context.Context, client *Client, fn requestFn) (c *cursor, err error) {
 	// Default to 10 items, which seems to be what Pivotal natively returns.
 	return &cursor{
+		ctx:       ctx,
 		client:    client,
 		requestFn: fn,
 		limit:     10,
 		lock:      &sync.Mutex{},
+		total:     -1,
 	}, nil
 }
 
@@ -42,7 +62,10 @@ func newCursor(client *Client, fn requestFn) (c *cursor, err error) {
 func (c *cursor) next(v interface{}) (resp *http.Response, err error) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
-	req := c.requestFn()
+	req := c.requestFn(c.ctx)
+	if req == nil {
+		return nil, errInvalidRequest
+	}
 
 	// Note: if we've already made requests, we always update
 	// the limit and offset fields to the current value. If we've
@@ -60,17 +83,22 @@ func (c *cursor) next(v interface{}) (resp *http.Response, err error) {
 		if values.Get("offset") == "" {
 			values.Set("offset", strconv.Itoa(c.offset))
 		}
+		c.start, _ = strconv.Atoi(values.Get("offset"))
 	}
 	req.URL.RawQuery = values.Encode()
 
-	// Do the request, decode JSON to v
-	resp, err = c.client.Do(req, &v)
+	resp, err = c.client.Do(req, v)
 	// increment request counter
 	c.reqCount++
 	if err != nil {
 		return nil, err
 	}
 
+	// Endpoints without pagination return everything in one response.
+	if resp.Header.Get("X-Tracker-Pagination-Total") == "" {
+		return resp, io.EOF
+	}
+
 	// Helper to extract and convert Header values that are Int's
 	getIntHeader := func(resp *http.Response, k string) int {
 		if err != nil {
@@ -99,10 +127,327 @@ func (c *cursor) next(v interface{}) (resp *http.Response, err error) {
 	} else {
 		c.offset = offset + limit
 	}
+	if limit > 0 {
+		c.limit = limit
+	}
+	c.total = total
 
 	// Return EOF if we have reached the end.
-	if c.offset >= total {
+	if c.offset >= total || returned == 0 {
 		err = io.EOF
 	}
 	return resp, err
 }
+
+// fetchWindow requests the page at the given offset and limit. Unlike
+// next it leaves the cursor state alone, so it may run concurrently.
+func (c *cursor) fetchWindow(ctx context.Context, offset, limit int, v interface{}) (*http.Response, error) {
+	req := c.requestFn(ctx)
+	if req == nil {
+		return nil, errInvalidRequest
+	}
+	values := req.URL.Query()
+	values.Set("limit", strconv.Itoa(limit))
+	values.Set("offset", strconv.Itoa(offset))
+	req.URL.RawQuery = values.Encode()
+	return c.client.Do(req, v)
+}
+
+// Cursor iterates over the items of a paginated endpoint, fetching
+// further pages from the API as they are needed.
+type Cursor[T any] struct {
+	*cursor
+	buff     []*T
+	done     bool
+	consumed int
+	lock     sync.Mutex
+
+	// Prefetching state, see Prefetch.
+	workers     int
+	pending     []chan pageResult[T]
+	sem         chan struct{}
+	prefetchCtx context.Context
+	cancel      context.CancelFunc
+}
+
+type pageResult[T any] struct {
+	items []*T
+	err   error
+}
+
+func newTypedCursor[T any](ctx context.Context, client *Client, fn requestFn) (*Cursor[T], error) {
+	cc, err := newCursor(ctx, client, fn)
+	return &Cursor[T]{cursor: cc, buff: make([]*T, 0)}, err
+}
+
+// Next returns the next item, or io.EOF once all items were returned.
+func (c *Cursor[T]) Next() (item *T, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for len(c.buff) == 0 {
+		if c.done {
+			return nil, io.EOF
+		}
+		if err = c.fetch(); err != nil {
+			return nil, err
+		}
+	}
+	item, c.buff = c.buff[0], c.buff[1:]
+	c.consumed++
+	return item, nil
+}
+
+// SetPageSize sets how many items are requested at once, at most
+// MaxPageSize. It has no effect once the first page was fetched.
+func (c *Cursor[T]) SetPageSize(n int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if n > MaxPageSize {
+		n = MaxPageSize
+	}
+	if n > 0 && c.reqCount == 0 {
+		c.limit = n
+	}
+}
+
+// Prefetch makes the cursor fetch the remaining pages concurrently, using
+// up to workers requests at a time, as soon as the first page reveals the
+// total number of items. Items are still returned in order. At most
+// workers pages are held in memory ahead of the caller; call Close to
+// abandon a prefetching cursor early, which All does on break.
+func (c *Cursor[T]) Prefetch(workers int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.workers = workers
+}
+
+// Close cancels any outstanding prefetch requests.
+func (c *Cursor[T]) Close() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.cancel != nil {
+		c.cancel()
+	}
+	c.done = true
+}
+
+// fetch appends the next page to the buffer.
+func (c *Cursor[T]) fetch() error {
+	if len(c.pending) > 0 {
+		var res pageResult[T]
+		select {
+		case res = <-c.pending[0]:
+		case <-c.prefetchCtx.Done():
+			res.err = c.prefetchCtx.Err()
+		}
+		c.pending = c.pending[1:]
+		select {
+		case <-c.sem:
+		default:
+		}
+		if res.err != nil {
+			c.cancel()
+			c.done = true
+			return res.err
+		}
+		if len(c.pending) == 0 {
+			c.done = true
+			c.cancel()
+		}
+		c.buff = append(c.buff, res.items...)
+		return nil
+	}
+
+	var page []*T
+	_, err := c.next(&page)
+	if err == io.EOF {
+		c.done = true
+		if c.total < 0 {
+			// Endpoints without pagination ignore the offset, so skip
+			// the items a resumed cursor has already returned.
+			c.total = len(page)
+			page = page[min(c.start, len(page)):]
+		}
+	} else if err != nil {
+		return err
+	} else if c.workers > 0 && c.reqCount == 1 {
+		c.startPrefetch()
+	}
+	c.buff = append(c.buff, page...)
+	return nil
+}
+
+// startPrefetch schedules a request for every remaining offset window.
+func (c *Cursor[T]) startPrefetch() {
+	ctx, cancel := context.WithCancel(c.ctx)
+	c.prefetchCtx, c.cancel = ctx, cancel
+	c.sem = make(chan struct{}, c.workers)
+	offset, limit, total := c.offset, c.limit, c.total
+	for off := offset; off < total; off += limit {
+		c.pending = append(c.pending, make(chan pageResult[T], 1))
+	}
+
+	pending := c.pending
+	go func() {
+		for i, ch := range pending {
+			select {
+			case c.sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			go func(off int, ch chan pageResult[T]) {
+				var page []*T
+				_, err := c.fetchWindow(ctx, off, limit, &page)
+				select {
+				case ch <- pageResult[T]{page, err}:
+				case <-ctx.Done():
+				}
+			}(offset+i*limit, ch)
+		}
+	}()
+}
+
+// All returns an iterator over the remaining items, for use with range.
+// Iteration stops after the first error, which is yielded with a nil item.
+// Breaking out of the loop closes the cursor.
+func (c *Cursor[T]) All() iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		for {
+			item, err := c.Next()
+			if err == io.EOF {
+				return
+			}
+			if !yield(item, err) {
+				c.Close()
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Total returns the number of items the endpoint reports, as given by
+// the X-Tracker-Pagination-Total header. It fetches the first page if no
+// request has been made yet.
+func (c *Cursor[T]) Total() (int, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.reqCount == 0 {
+		if err := c.fetch(); err != nil {
+			return 0, err
+		}
+	}
+	return c.total, nil
+}
+
+var ErrPositionMismatch = errors.New("cursor position belongs to a different endpoint")
+
+// CursorPosition is a serializable checkpoint of a cursor: the endpoint,
+// the filter options it was created with and the offset of the next item
+// to be returned. It marshals to an opaque token, see String.
+type CursorPosition struct {
+	Path   string     `json:"path"`
+	Query  url.Values `json:"query,omitempty"`
+	Offset int        `json:"offset"`
+	Limit  int        `json:"limit"`
+}
+
+// positionToken has the fields of CursorPosition but none of its methods,
+// so that it encodes as a plain JSON object.
+type positionToken CursorPosition
+
+// String returns the position as an opaque token.
+func (p CursorPosition) String() string {
+	b, _ := json.Marshal(positionToken(p))
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func (p CursorPosition) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+func (p *CursorPosition) UnmarshalText(text []byte) error {
+	pos, err := ParseCursorPosition(string(text))
+	if err != nil {
+		return err
+	}
+	*p = pos
+	return nil
+}
+
+// ParseCursorPosition decodes a token returned by CursorPosition.String.
+func ParseCursorPosition(token string) (pos CursorPosition, err error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return pos, err
+	}
+	var raw positionToken
+	err = json.Unmarshal(b, &raw)
+	return CursorPosition(raw), err
+}
+
+// Position returns the checkpoint of the cursor. A cursor created from it
+// resumes with the item Next would return now.
+func (c *Cursor[T]) Position() (CursorPosition, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	req := c.requestFn(context.Background())
+	if req == nil {
+		return CursorPosition{}, errInvalidRequest
+	}
+
+	query := req.URL.Query()
+	start, limit := c.start, c.limit
+	if c.reqCount == 0 {
+		start = c.offset
+		if v, err := strconv.Atoi(query.Get("offset")); err == nil {
+			start = v
+		}
+		if v, err := strconv.Atoi(query.Get("limit")); err == nil {
+			limit = v
+		}
+	}
+	query.Del("limit")
+	query.Del("offset")
+	return CursorPosition{
+		Path:   req.URL.Path,
+		Query:  query,
+		Offset: start + c.consumed,
+		Limit:  limit,
+	}, nil
+}
+
+// resumeTypedCursor creates a cursor continuing at pos. fn must build the
+// unfiltered request of the endpoint; the filters are restored from pos.
+func resumeTypedCursor[T any](ctx context.Context, client *Client, fn requestFn, pos CursorPosition) (*Cursor[T], error) {
+	req := fn(ctx)
+	if req == nil {
+		return nil, errInvalidRequest
+	}
+	if req.URL.Path != pos.Path {
+		return nil, ErrPositionMismatch
+	}
+
+	resume := func(ctx context.Context) *http.Request {
+		req := fn(ctx)
+		if req != nil {
+			query := url.Values{}
+			for k, vs := range pos.Query {
+				query[k] = append([]string(nil), vs...)
+			}
+			req.URL.RawQuery = query.Encode()
+		}
+		return req
+	}
+	c, err := newTypedCursor[T](ctx, client, resume)
+	if err != nil {
+		return nil, err
+	}
+	c.offset = pos.Offset
+	if pos.Limit > 0 {
+		c.limit = pos.Limit
+	}
+	return c, nil
+}
@@ -0,0 +1,157 @@
+// Copyright (C) 2015 Scott Devoid
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package pivotal_test
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/salsita/go-pivotaltracker/v5/pivotal"
+	"github.com/salsita/go-pivotaltracker/v5/pivotal/pivotaltest"
+)
+
+// newStoryServer returns a fake Tracker holding n stories named by their
+// position in the backlog.
+func newStoryServer(t *testing.T, n int) (*pivotaltest.Server, int) {
+	srv := pivotaltest.NewServer()
+	t.Cleanup(srv.Close)
+	p := srv.AddProject(pivotal.Project{Name: "Test"})
+	for i := 0; i < n; i++ {
+		srv.AddStory(p.Id, pivotal.Story{Name: fmt.Sprintf("story %d", i)})
+	}
+	return srv, p.Id
+}
+
+func TestCursorPrefetchInOrder(t *testing.T) {
+	const n = 63 // 13 pages of 5
+	srv, projectId := newStoryServer(t, n)
+
+	cur, err := srv.Client().Project(projectId).Stories.Iterate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cur.SetPageSize(5)
+	cur.Prefetch(3)
+
+	i := 0
+	for story, err := range cur.All() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := fmt.Sprintf("story %d", i); story.Name != want {
+			t.Fatalf("item %d is %q, want %q", i, story.Name, want)
+		}
+		i++
+	}
+	if i != n {
+		t.Errorf("got %d stories, want %d", i, n)
+	}
+}
+
+// stallingTransport holds every request past the first page until its
+// context is cancelled.
+type stallingTransport struct {
+	started   int32
+	cancelled int32
+}
+
+func (t *stallingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if off := req.URL.Query().Get("offset"); off == "" || off == "0" {
+		return http.DefaultTransport.RoundTrip(req)
+	}
+	atomic.AddInt32(&t.started, 1)
+	<-req.Context().Done()
+	atomic.AddInt32(&t.cancelled, 1)
+	return nil, req.Context().Err()
+}
+
+func TestCursorBreakCancelsPrefetch(t *testing.T) {
+	srv, projectId := newStoryServer(t, 50)
+	transport := &stallingTransport{}
+	client := srv.Client(pivotal.WithHTTPClient(&http.Client{Transport: transport}))
+
+	cur, err := client.Project(projectId).Stories.Iterate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cur.SetPageSize(5)
+	cur.Prefetch(3)
+	for _, err := range cur.All() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Leave the loop once all three workers are waiting.
+		waitFor(t, func() bool { return atomic.LoadInt32(&transport.started) == 3 })
+		break
+	}
+
+	waitFor(t, func() bool { return atomic.LoadInt32(&transport.cancelled) == 3 })
+	time.Sleep(50 * time.Millisecond)
+	if started := atomic.LoadInt32(&transport.started); started != 3 {
+		t.Errorf("%d requests started after the loop was left, want 3", started)
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestCursorPositionDuringPrefetch(t *testing.T) {
+	srv, projectId := newStoryServer(t, 40)
+	stories := srv.Client().Project(projectId).Stories
+
+	cur, err := stories.Iterate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cur.SetPageSize(5)
+	cur.Prefetch(3)
+	for i := 0; i < 17; i++ {
+		if _, err := cur.Next(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pos, err := cur.Position()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cur.Close()
+
+	token, err := pos.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pos, err = pivotal.ParseCursorPosition(string(token))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resumed, err := stories.IterateFrom(pos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := 17
+	for story, err := range resumed.All() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := fmt.Sprintf("story %d", i); story.Name != want {
+			t.Fatalf("resumed at %q, want %q", story.Name, want)
+		}
+		i++
+	}
+	if i != 40 {
+		t.Errorf("resumed cursor ended at %d, want 40", i)
+	}
+}
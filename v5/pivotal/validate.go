@@ -0,0 +1,172 @@
+// Copyright (C) 2015 Scott Devoid
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package pivotal
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// maxNameLength is the longest story or epic name Tracker accepts.
+const maxNameLength = 5000
+
+var (
+	storyTypes = []string{
+		StoryTypeFeature, StoryTypeBug, StoryTypeChore, StoryTypeRelease,
+	}
+	storyStates = []string{
+		StoryStateUnscheduled, StoryStatePlanned, StoryStateUnstarted, StoryStateStarted,
+		StoryStateFinished, StoryStateDelivered, StoryStateAccepted, StoryStateRejected,
+	}
+)
+
+// WithValidation makes the Client validate stories and epics before
+// they are written, see Story.Validate. The configuration of each project
+// is fetched once and cached for the lifetime of the Client.
+func WithValidation() ClientOption {
+	return func(c *Client) {
+		c.validate = true
+	}
+}
+
+// problems collects field problems into a *ValidationError.
+type problems []FieldProblem
+
+func (p *problems) add(field, format string, args ...interface{}) {
+	*p = append(*p, FieldProblem{Field: field, Problem: fmt.Sprintf(format, args...)})
+}
+
+func (p problems) err() error {
+	if len(p) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: p}
+}
+
+// Validate checks the story for values Tracker would reject and returns
+// a *ValidationError listing every offending field. Only the fields that
+// are set are checked, so partial updates validate as well. project may
+// be nil, in which case the point scale is not checked.
+func (s *Story) Validate(project *Project) error {
+	var p problems
+	if len(s.Name) > maxNameLength {
+		p.add("name", "must be at most %d characters", maxNameLength)
+	}
+	if s.Type != "" && !contains(storyTypes, s.Type) {
+		p.add("story_type", "must be one of %s", strings.Join(storyTypes, ", "))
+	}
+	if s.State != "" && !contains(storyStates, s.State) {
+		p.add("current_state", "must be one of %s", strings.Join(storyStates, ", "))
+	}
+	if s.Deadline != nil && s.Type != "" && s.Type != StoryTypeRelease {
+		p.add("deadline", "can only be set on release stories")
+	}
+	if s.Labels != nil {
+		for _, l := range *s.Labels {
+			if l != nil && l.Name == "" && l.Id == 0 {
+				p.add("labels", "must have a name or an id")
+				break
+			}
+		}
+	}
+
+	if s.Estimate != nil {
+		switch {
+		case *s.Estimate < 0:
+			p.add("estimate", "must not be negative")
+		case s.Type == StoryTypeRelease:
+			p.add("estimate", "release stories cannot be estimated")
+		case project != nil && (s.Type == StoryTypeBug || s.Type == StoryTypeChore) &&
+			!project.BugsAndChoresAreEstimatable:
+			p.add("estimate", "%ss cannot be estimated in this project", s.Type)
+		case project != nil && project.PointScale != "" && !onPointScale(project.PointScale, *s.Estimate):
+			p.add("estimate", "must be one of %s", project.PointScale)
+		}
+	}
+	return p.err()
+}
+
+// Validate checks the task for values Tracker would reject.
+func (t *Task) Validate() error {
+	var p problems
+	if t.Description == "" {
+		p.add("description", "can't be blank")
+	}
+	if t.Position < 0 {
+		p.add("position", "must be positive")
+	}
+	return p.err()
+}
+
+// Validate checks the epic request for values Tracker would reject.
+func (e *EpicRequest) Validate() error {
+	var p problems
+	if len(e.Name) > maxNameLength {
+		p.add("name", "must be at most %d characters", maxNameLength)
+	}
+	if e.BeforeId != 0 && e.BeforeId == e.AfterId {
+		p.add("before_id", "must differ from after_id")
+	}
+	for _, c := range e.Comments {
+		if c != nil && c.Text == "" && len(c.FileAttachmentIds) == 0 && len(c.GoogleAttachmentIds) == 0 {
+			p.add("comments", "can't be blank")
+			break
+		}
+	}
+	return p.err()
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// onPointScale reports whether estimate is one of the comma separated
+// values of scale.
+func onPointScale(scale string, estimate float64) bool {
+	for _, v := range strings.Split(scale, ",") {
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err == nil && f == estimate {
+			return true
+		}
+	}
+	return false
+}
+
+// projectConfig returns the project with the given ID, fetching it on
+// first use.
+func (c *Client) projectConfig(ctx context.Context, projectId string) (*Project, error) {
+	if p, ok := c.projectCache.Load(projectId); ok {
+		return p.(*Project), nil
+	}
+	id, err := strconv.Atoi(projectId)
+	if err != nil {
+		return nil, err
+	}
+	p, _, err := c.Projects.GetContext(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	c.projectCache.Store(projectId, p)
+	return p, nil
+}
+
+// validateStory validates story if validation is enabled on the Client.
+func (service *StoryService) validateStory(ctx context.Context, story *Story) error {
+	if !service.client.validate {
+		return nil
+	}
+	project, err := service.client.projectConfig(ctx, service.projectId)
+	if err != nil {
+		return err
+	}
+	return story.Validate(project)
+}
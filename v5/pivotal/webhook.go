@@ -0,0 +1,205 @@
+// Copyright (C) 2015 Scott Devoid
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package pivotal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultWebhookVersion is the payload version registered when none is given.
+const DefaultWebhookVersion = "v5"
+
+type Webhook struct {
+	Id             int        `json:"id,omitempty"`
+	ProjectId      int        `json:"project_id,omitempty"`
+	WebhookURL     string     `json:"webhook_url,omitempty"`
+	WebhookVersion string     `json:"webhook_version,omitempty"`
+	CreatedAt      *time.Time `json:"created_at,omitempty"`
+	UpdatedAt      *time.Time `json:"updated_at,omitempty"`
+	Kind           string     `json:"kind,omitempty"`
+}
+
+// WebhookService manages the webhooks configured for a project.
+type WebhookService struct {
+	client    *Client
+	projectId string
+}
+
+func newWebhookService(client *Client, projectId string) *WebhookService {
+	return &WebhookService{client, projectId}
+}
+
+func (s *WebhookService) List() ([]*Webhook, *http.Response, error) {
+	return s.ListContext(context.Background())
+}
+
+func (s *WebhookService) ListContext(ctx context.Context) ([]*Webhook, *http.Response, error) {
+	u := fmt.Sprintf("projects/%v/webhooks", s.projectId)
+	req, err := s.client.NewRequestContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	var webhooks []*Webhook
+	resp, err := s.client.Do(req, &webhooks)
+	if err != nil {
+		return nil, resp, err
+	}
+	return webhooks, resp, err
+}
+
+// Create registers url to receive the project's activity. An empty
+// version selects DefaultWebhookVersion.
+func (s *WebhookService) Create(url, version string) (*Webhook, *http.Response, error) {
+	return s.CreateContext(context.Background(), url, version)
+}
+
+func (s *WebhookService) CreateContext(ctx context.Context, url, version string) (*Webhook, *http.Response, error) {
+	if url == "" {
+		return nil, nil, &ErrFieldNotSet{"webhook_url"}
+	}
+	if version == "" {
+		version = DefaultWebhookVersion
+	}
+	w := Webhook{WebhookURL: url, WebhookVersion: version}
+	u := fmt.Sprintf("projects/%v/webhooks", s.projectId)
+	req, err := s.client.NewRequestContext(ctx, "POST", u, w)
+	if err != nil {
+		return nil, nil, err
+	}
+	var webhook Webhook
+	resp, err := s.client.Do(req, &webhook)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &webhook, resp, err
+}
+
+func (s *WebhookService) Delete(id int) (*http.Response, error) {
+	return s.DeleteContext(context.Background(), id)
+}
+
+func (s *WebhookService) DeleteContext(ctx context.Context, id int) (*http.Response, error) {
+	u := fmt.Sprintf("projects/%v/webhooks/%v", s.projectId, id)
+	req, err := s.client.NewRequestContext(ctx, "DELETE", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.client.Do(req, nil)
+}
+
+// maxWebhookBody limits the size of an accepted webhook payload.
+const maxWebhookBody = 10 << 20
+
+// WebhookFunc handles a single activity delivered to a webhook.
+// Returning an error makes the handler respond with a server error.
+type WebhookFunc func(ctx context.Context, activity *Activity) error
+
+// WebhookHandler is an http.Handler receiving Tracker webhook posts.
+// It decodes each payload into an Activity and dispatches it to the
+// functions registered for its kind, e.g. ActivityKindStoryUpdate.
+type WebhookHandler struct {
+	lock     sync.RWMutex
+	handlers map[string][]WebhookFunc
+	fallback []WebhookFunc
+}
+
+func NewWebhookHandler() *WebhookHandler {
+	return &WebhookHandler{handlers: make(map[string][]WebhookFunc)}
+}
+
+// Handle registers fn for activities of the given kind.
+func (h *WebhookHandler) Handle(kind string, fn WebhookFunc) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.handlers[kind] = append(h.handlers[kind], fn)
+}
+
+// HandleAll registers fn for activities of every kind.
+func (h *WebhookHandler) HandleAll(fn WebhookFunc) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.fallback = append(h.fallback, fn)
+}
+
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	activity, err := ParseWebhook(io.LimitReader(r.Body, maxWebhookBody))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.lock.RLock()
+	fns := make([]WebhookFunc, 0, len(h.handlers[activity.Kind])+len(h.fallback))
+	fns = append(fns, h.handlers[activity.Kind]...)
+	fns = append(fns, h.fallback...)
+	h.lock.RUnlock()
+
+	for _, fn := range fns {
+		if err := fn(r.Context(), activity); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// ParseWebhook decodes a webhook payload. Tracker sends timestamps in
+// webhook payloads as milliseconds since the epoch; these are converted
+// so that they decode into the time.Time fields of Story, Epic and so on.
+func ParseWebhook(r io.Reader) (*Activity, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var raw interface{}
+	if err := dec.Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	if err := json.NewEncoder(buf).Encode(normalizeTimestamps("", raw)); err != nil {
+		return nil, err
+	}
+
+	var activity Activity
+	if err := json.NewDecoder(buf).Decode(&activity); err != nil {
+		return nil, err
+	}
+	return &activity, nil
+}
+
+// normalizeTimestamps replaces numeric values of timestamp attributes
+// with RFC 3339 strings, recursing into objects and arrays.
+func normalizeTimestamps(key string, v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = normalizeTimestamps(k, e)
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = normalizeTimestamps("", e)
+		}
+	case json.Number:
+		if strings.HasSuffix(key, "_at") || key == "deadline" {
+			if ms, err := v.Int64(); err == nil {
+				return time.UnixMilli(ms).UTC().Format(time.RFC3339Nano)
+			}
+		}
+	}
+	return v
+}
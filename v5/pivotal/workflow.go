@@ -0,0 +1,148 @@
+// Copyright (C) 2015 Scott Devoid
+// Use of this source code is governed by the MIT License.
+// The license can be found in the LICENSE file.
+
+package pivotal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// transitions lists, per story type, the states a story may move to from
+// each state.
+var transitions = map[string]map[string][]string{
+	StoryTypeFeature: {
+		StoryStateUnscheduled: {StoryStateStarted},
+		StoryStatePlanned:     {StoryStateStarted},
+		StoryStateUnstarted:   {StoryStateStarted},
+		StoryStateStarted:     {StoryStateFinished},
+		StoryStateFinished:    {StoryStateDelivered},
+		StoryStateDelivered:   {StoryStateAccepted, StoryStateRejected},
+		StoryStateRejected:    {StoryStateStarted},
+	},
+	StoryTypeBug: {
+		StoryStateUnscheduled: {StoryStateStarted},
+		StoryStatePlanned:     {StoryStateStarted},
+		StoryStateUnstarted:   {StoryStateStarted},
+		StoryStateStarted:     {StoryStateFinished},
+		StoryStateFinished:    {StoryStateDelivered},
+		StoryStateDelivered:   {StoryStateAccepted, StoryStateRejected},
+		StoryStateRejected:    {StoryStateStarted},
+	},
+	StoryTypeChore: {
+		StoryStateUnscheduled: {StoryStateStarted},
+		StoryStatePlanned:     {StoryStateStarted},
+		StoryStateUnstarted:   {StoryStateStarted},
+		StoryStateStarted:     {StoryStateAccepted},
+	},
+	StoryTypeRelease: {
+		StoryStateUnscheduled: {StoryStateAccepted},
+		StoryStatePlanned:     {StoryStateAccepted},
+		StoryStateUnstarted:   {StoryStateAccepted},
+	},
+}
+
+// ErrIllegalTransition is returned when a story cannot move to a state.
+type ErrIllegalTransition struct {
+	StoryId int
+	Type    string
+	From    string
+	To      string
+	Reason  string
+}
+
+func (err *ErrIllegalTransition) Error() string {
+	return fmt.Sprintf("%s %d cannot go from '%s' to '%s': %s",
+		err.Type, err.StoryId, err.From, err.To, err.Reason)
+}
+
+// CanTransition reports whether Tracker allows story to move to the given
+// state, returning an *ErrIllegalTransition if it does not.
+func CanTransition(story *Story, to string) error {
+	illegal := func(reason string) error {
+		return &ErrIllegalTransition{
+			StoryId: story.Id,
+			Type:    story.Type,
+			From:    story.State,
+			To:      to,
+			Reason:  reason,
+		}
+	}
+
+	byState, ok := transitions[story.Type]
+	if !ok {
+		return illegal("unknown story type")
+	}
+	if !contains(byState[story.State], to) {
+		return illegal(fmt.Sprintf("%ss in state '%s' cannot move there", story.Type, story.State))
+	}
+	if to == StoryStateStarted && story.Type == StoryTypeFeature && story.Estimate == nil {
+		return illegal("unestimated features cannot be started")
+	}
+	return nil
+}
+
+func (service *StoryService) Start(storyId int) (*Story, *http.Response, error) {
+	return service.StartContext(context.Background(), storyId)
+}
+
+func (service *StoryService) StartContext(ctx context.Context, storyId int) (*Story, *http.Response, error) {
+	return service.transition(ctx, storyId, StoryStateStarted)
+}
+
+func (service *StoryService) Finish(storyId int) (*Story, *http.Response, error) {
+	return service.FinishContext(context.Background(), storyId)
+}
+
+func (service *StoryService) FinishContext(ctx context.Context, storyId int) (*Story, *http.Response, error) {
+	return service.transition(ctx, storyId, StoryStateFinished)
+}
+
+func (service *StoryService) Deliver(storyId int) (*Story, *http.Response, error) {
+	return service.DeliverContext(context.Background(), storyId)
+}
+
+func (service *StoryService) DeliverContext(ctx context.Context, storyId int) (*Story, *http.Response, error) {
+	return service.transition(ctx, storyId, StoryStateDelivered)
+}
+
+func (service *StoryService) Accept(storyId int) (*Story, *http.Response, error) {
+	return service.AcceptContext(context.Background(), storyId)
+}
+
+func (service *StoryService) AcceptContext(ctx context.Context, storyId int) (*Story, *http.Response, error) {
+	return service.transition(ctx, storyId, StoryStateAccepted)
+}
+
+// Reject rejects a delivered story. A non-empty reason is added to the
+// story as a comment.
+func (service *StoryService) Reject(storyId int, reason string) (*Story, *http.Response, error) {
+	return service.RejectContext(context.Background(), storyId, reason)
+}
+
+func (service *StoryService) RejectContext(ctx context.Context, storyId int, reason string) (*Story, *http.Response, error) {
+	story, resp, err := service.transition(ctx, storyId, StoryStateRejected)
+	if err != nil || reason == "" {
+		return story, resp, err
+	}
+	_, resp, err = service.AddCommentContext(ctx, storyId, &Comment{Text: reason})
+	if err != nil {
+		return story, resp, err
+	}
+	return story, resp, nil
+}
+
+// transition fetches the story, checks that it may move to the given
+// state and updates it.
+func (service *StoryService) transition(ctx context.Context, storyId int, to string) (*Story, *http.Response, error) {
+	story, resp, err := service.GetContext(ctx, storyId)
+	if err != nil {
+		return nil, resp, err
+	}
+	if err := CanTransition(story, to); err != nil {
+		return nil, resp, err
+	}
+	return service.UpdateContext(ctx, storyId, &Story{State: to})
+}